@@ -2,31 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"go-competiotion-crawler/internal/request"
 	"go-competiotion-crawler/internal/worker_pool"
-	"io"
 	"net/http"
 	"time"
 )
 
-func makeRequestTask(ctx context.Context, url string) func() (string, error) {
-	return func() (string, error) {
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			return "", err
-		}
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return "", err
-		}
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return "", err
-		}
-		return string(body), nil
-	}
-}
-
 func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
@@ -43,7 +26,7 @@ func main() {
 
 	handles := make([]worker_pool.Handle[string], 0, 200)
 	for i := range 200 {
-		h, err := pool.Submit(makeRequestTask(ctx, "https://"+urls[i%len(urls)]))
+		h, err := pool.Submit(request.NewRequestTask(ctx, http.DefaultClient, http.MethodGet, "https://"+urls[i%len(urls)], nil))
 		if err != nil {
 			panic(fmt.Sprintf("submit error: %v", err))
 		}
@@ -51,6 +34,13 @@ func main() {
 	}
 	pool.Done()
 
+	// Done is final: later submissions are refused with ErrPoolClosed and
+	// never run, they do not panic. Shutdown followed by Reset makes the
+	// pool usable again.
+	if _, err := pool.Submit(func() (string, error) { return "", nil }); errors.Is(err, worker_pool.ErrPoolClosed) {
+		fmt.Println("submit after Done:", err)
+	}
+
 	for i, h := range handles {
 		v, err := h.Get()
 		if err != nil {
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"go-competiotion-crawler/internal/worker_pool"
+	"time"
+)
+
+// probeTimeout caps every AutoTune probe.
+const probeTimeout = 3 * time.Second
+
+func AutoTune(sampleURLs []string, limit int) (worker_pool.Workers, worker_pool.Capacity) {
+	return defaultCrawler.AutoTune(sampleURLs, limit)
+}
+
+// AutoTune fetches sampleURLs with 1, 2, 4 ... and finally limit workers and
+// returns the worker count with the best measured throughput, along with a
+// capacity keeping each of them supplied with a queued task. Every probe
+// takes at most probeTimeout and bypasses the cache; failed requests count
+// as done, since they free the worker as well. Without samples it returns a
+// single worker.
+func (c *Crawler) AutoTune(sampleURLs []string, limit int) (worker_pool.Workers, worker_pool.Capacity) {
+	if len(sampleURLs) == 0 {
+		return 1, 2
+	}
+	best, bestRate := 1, 0.0
+	for n := 1; ; n = min(n*2, limit) {
+		if rate := c.probe(sampleURLs, n); rate > bestRate {
+			best, bestRate = n, rate
+		}
+		if n >= limit {
+			break
+		}
+	}
+	return worker_pool.Workers(best), worker_pool.Capacity(2 * best)
+}
+
+// probe returns the number of requests per second done with n workers.
+func (c *Crawler) probe(urls []string, n int) float64 {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	pool := worker_pool.NewWorkerPoolWithContext[*Response](ctx, worker_pool.Workers(n), worker_pool.Capacity(len(urls)))
+	start := time.Now()
+	handles := make([]worker_pool.Handle[*Response], 0, len(urls))
+	for _, url := range urls {
+		h, err := pool.Submit(func() (*Response, error) {
+			return c.doFetch(ctx, url)
+		})
+		if err != nil {
+			break
+		}
+		handles = append(handles, h)
+	}
+	pool.Done()
+
+	done := 0
+	for i := range handles {
+		if _, err := handles[i].Get(); !errors.Is(err, context.DeadlineExceeded) {
+			done++
+		}
+	}
+	return float64(done) / time.Since(start).Seconds()
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAutoTune(t *testing.T) {
+	var mu sync.Mutex
+	var running, peak int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(samplePayload))
+		mu.Lock()
+		running--
+		mu.Unlock()
+	}))
+	defer srv.Close()
+	urls := make([]string, 12)
+	for i := range urls {
+		urls[i] = srv.URL + "?directionId=1"
+	}
+	c := NewCrawler(srv.Client(), nil)
+
+	for _, limit := range []int{1, 3, 5, 6, 8} {
+		mu.Lock()
+		peak = 0
+		mu.Unlock()
+		start := time.Now()
+		workers, capacity := c.AutoTune(urls, limit)
+		if elapsed := time.Since(start); elapsed > probeTimeout {
+			t.Errorf("limit %d: took %v", limit, elapsed)
+		}
+		if workers < 1 || int(workers) > limit || int(capacity) != 2*int(workers) {
+			t.Errorf("limit %d: got %d workers, capacity %d", limit, workers, capacity)
+		}
+		// The limit itself is probed, not only the powers of two below it.
+		mu.Lock()
+		if peak != limit {
+			t.Errorf("limit %d: at most %d concurrent requests", limit, peak)
+		}
+		mu.Unlock()
+	}
+	if w, _ := c.AutoTune(nil, 8); w != 1 {
+		t.Errorf("without samples: %d workers", w)
+	}
+}
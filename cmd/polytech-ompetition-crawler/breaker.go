@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without making a request while the enrollment
+// host is considered down.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker opens after threshold consecutive failures and rejects
+// requests for cooldown. Then it lets a single probe through: its success
+// closes the breaker, its failure opens it for another cooldown.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+}
+
+// WithCircuitBreaker stops requests to the server for cooldown after
+// threshold consecutive network errors or 5xx responses.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Crawler) {
+		c.breaker = &circuitBreaker{
+			threshold: max(threshold, 1),
+			cooldown:  cooldown,
+		}
+	}
+}
+
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+	case breakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record reports the outcome of an allowed request. Requests that neither
+// succeeded nor failed because of the server (e.g. cancelled by the caller)
+// only free the probe slot.
+func (b *circuitBreaker) record(failed, neutral bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerHalfOpen {
+		b.probing = false
+	}
+	if neutral {
+		return
+	}
+	switch {
+	case !failed:
+		b.state = breakerClosed
+		b.failures = 0
+	case b.state == breakerHalfOpen:
+		b.open()
+	default:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.open()
+		}
+	}
+}
+
+func (b *circuitBreaker) open() {
+	b.state = breakerOpen
+	b.openedAt = time.Now()
+	b.failures = 0
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	var requests atomic.Int64
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(samplePayload))
+	}))
+	defer srv.Close()
+
+	const cooldown = 50 * time.Millisecond
+	c := NewCrawler(srv.Client(), nil, WithCircuitBreaker(3, cooldown))
+	url := srv.URL + "?directionId=1"
+	for range 3 {
+		if _, err := c.GetCompetitionList(context.Background(), url); errors.Is(err, ErrCircuitOpen) || err == nil {
+			t.Fatalf("err = %v, want a status error", err)
+		}
+	}
+	for range 5 {
+		if _, err := c.GetCompetitionList(context.Background(), url); !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("err = %v, want ErrCircuitOpen", err)
+		}
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("server got %d requests, want 3", n)
+	}
+
+	healthy.Store(true)
+	time.Sleep(cooldown)
+	if _, err := c.GetCompetitionList(context.Background(), url); err != nil {
+		t.Fatalf("probe after cooldown: %v", err)
+	}
+	if _, err := c.GetCompetitionList(context.Background(), url); err != nil {
+		t.Errorf("after recovery: %v", err)
+	}
+}
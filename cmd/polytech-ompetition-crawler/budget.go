@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"go-competiotion-crawler/internal/worker_pool"
+	"time"
+)
+
+func CrawlWithBudget(ctx context.Context, dirs []uint64, budget time.Duration) (UserDb, []error) {
+	return defaultCrawler.CrawlWithBudget(ctx, dirs, budget)
+}
+
+// CrawlWithBudget crawls dirs for at most budget and returns whatever was
+// collected by then. Directions that didn't finish in time are reported with
+// context.DeadlineExceeded; their requests are cancelled and any task still
+// queued is dropped.
+func (c *Crawler) CrawlWithBudget(ctx context.Context, dirs []uint64, budget time.Duration) (UserDb, []error) {
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+	pool := worker_pool.NewWorkerPoolWithContext[[]User](ctx, maxWorkers, worker_pool.Capacity(len(dirs)))
+	return c.crawl(ctx, pool, dirs)
+}
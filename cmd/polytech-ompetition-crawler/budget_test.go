@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCrawlWithBudgetReturnsPartialDb(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("directionId") == "201" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		w.Write([]byte(samplePayload))
+	}))
+	defer srv.Close()
+	c := NewCrawler(redirectedClient(t, srv), nil)
+
+	start := time.Now()
+	db, errs := c.CrawlWithBudget(context.Background(), []uint64{200, 201, 202}, 200*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %v", elapsed)
+	}
+	if n := len(db["111-111-111 11"]); n != 2 {
+		t.Errorf("got %d finished directions, want 2", n)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("errs = %v, want one deadline error", errs)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// responseCache keeps successful responses by request URL for ttl.
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	resp    Response
+	expires time.Time
+}
+
+// WithCache serves repeated requests for the same URL from memory for ttl
+// after a successful response, without hitting the server.
+func WithCache(ttl time.Duration) Option {
+	return func(c *Crawler) {
+		c.cache = &responseCache{
+			ttl:     ttl,
+			entries: make(map[string]cacheEntry),
+		}
+	}
+}
+
+// get returns a copy of the cached response, so callers may modify it.
+func (rc *responseCache) get(url string) (*Response, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	e, ok := rc.entries[url]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(rc.entries, url)
+		return nil, false
+	}
+	resp := e.resp
+	resp.Users = slices.Clone(e.resp.Users)
+	return &resp, true
+}
+
+func (rc *responseCache) put(url string, resp *Response) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	e := cacheEntry{
+		resp:    *resp,
+		expires: time.Now().Add(rc.ttl),
+	}
+	e.resp.Users = slices.Clone(resp.Users)
+	rc.entries[url] = e
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCacheServesRepeatedURL(t *testing.T) {
+	srv := newRatingServer(t, samplePayload)
+	tracker := &closeTracker{next: srv.Client().Transport}
+	c := NewCrawler(&http.Client{Transport: tracker}, nil, WithCache(50*time.Millisecond))
+	url := srv.URL + "?directionId=1"
+
+	first, err := c.GetCompetitionList(context.Background(), url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first[0].FullScore = 0
+	second, err := c.GetCompetitionList(context.Background(), url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := tracker.opened.Load(); n != 1 {
+		t.Errorf("RoundTripper called %d times within the TTL, want 1", n)
+	}
+	if second[0].FullScore != 270 {
+		t.Error("modifying a result changed the cached response")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := c.GetCompetitionList(context.Background(), url); err != nil {
+		t.Fatal(err)
+	}
+	if n := tracker.opened.Load(); n != 2 {
+		t.Errorf("RoundTripper called %d times after the TTL, want 2", n)
+	}
+}
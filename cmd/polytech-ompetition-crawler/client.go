@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// newTunedTransport returns a transport meant for many requests to one host:
+// http.DefaultTransport keeps only two idle connections per host, so under
+// concurrent use most connections are closed and dialed (and TLS-handshaked)
+// again on the next request.
+func newTunedTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConnsPerHost = 4
+	t.TLSHandshakeTimeout = 10 * time.Second
+	t.ResponseHeaderTimeout = 30 * time.Second
+	return t
+}
+
+// WithClientPerWorker gives each of workers concurrent requests its own copy
+// of the Crawler client with a separate transport, so that workers do not
+// contend for one connection pool. A request waits for a free client, hence
+// workers should match the worker pool size.
+func WithClientPerWorker(workers int) Option {
+	return func(c *Crawler) {
+		c.perWorker = workers
+	}
+}
+
+// WithProxy routes all requests through proxyURL, such as
+// "http://proxy.local:3128". Without it the HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables are respected. An invalid proxyURL makes
+// every request fail with the parse error.
+func WithProxy(proxyURL string) Option {
+	return func(c *Crawler) {
+		u, err := url.Parse(proxyURL)
+		if err == nil && u.Host == "" {
+			err = fmt.Errorf("proxy %q has no host", proxyURL)
+		}
+		c.proxy = func(*http.Request) (*url.URL, error) {
+			if err != nil {
+				return nil, fmt.Errorf("invalid proxy: %w", err)
+			}
+			return u, nil
+		}
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the Crawler transports, for
+// example to trust a private root CA or to present a client certificate.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Crawler) {
+		c.tlsConfig = cfg
+	}
+}
+
+// configureTransport applies the transport options to t.
+func (c *Crawler) configureTransport(t *http.Transport) {
+	if c.proxy != nil {
+		t.Proxy = c.proxy
+	}
+	if c.tlsConfig != nil {
+		t.TLSClientConfig = c.tlsConfig.Clone()
+	}
+}
+
+// applyTransportOptions makes the Crawler client use the proxy and TLS
+// options. The client given to NewCrawler is copied rather than modified
+// since it is usually shared, http.DefaultClient for one. A client with a
+// custom RoundTripper is left alone.
+func (c *Crawler) applyTransportOptions() {
+	if c.proxy == nil && c.tlsConfig == nil {
+		return
+	}
+	var t *http.Transport
+	switch rt := c.client.Transport.(type) {
+	case nil:
+		t = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		t = rt.Clone()
+	default:
+		c.logger.Warn("transport options not applied to a client with a custom transport")
+		return
+	}
+	c.configureTransport(t)
+	client := *c.client
+	client.Transport = t
+	c.client = &client
+}
+
+// buildWorkerClients creates the per-worker clients. Each is a copy of the
+// Crawler client, so its Timeout, Jar and CheckRedirect are kept, with a
+// transport of its own: a clone of the injected *http.Transport, or a tuned
+// one if the client uses the default transport. A custom RoundTripper cannot
+// be cloned, so such a client is shared by all workers instead.
+func (c *Crawler) buildWorkerClients() {
+	if c.perWorker < 1 {
+		return
+	}
+	newTransport := newTunedTransport
+	switch rt := c.client.Transport.(type) {
+	case nil:
+	case *http.Transport:
+		newTransport = rt.Clone
+	default:
+		c.logger.Warn("per-worker clients not used with a custom transport")
+		return
+	}
+	c.clients = make(chan *http.Client, c.perWorker)
+	for range c.perWorker {
+		t := newTransport()
+		c.configureTransport(t)
+		client := *c.client
+		client.Transport = t
+		c.clients <- &client
+	}
+}
+
+// acquireClient returns the client to make a request with and a function
+// giving it back once the request is done.
+func (c *Crawler) acquireClient(ctx context.Context) (*http.Client, func(), error) {
+	if c.clients == nil {
+		return c.client, func() {}, nil
+	}
+	select {
+	case client := <-c.clients:
+		return client, func() { c.clients <- client }, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
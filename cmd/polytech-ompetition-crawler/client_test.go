@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClientPerWorkerKeepsInjectedClient(t *testing.T) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	injected := &http.Transport{MaxConnsPerHost: 7}
+	errStop := errors.New("stop")
+	c := NewCrawler(&http.Client{
+		Transport:     injected,
+		Jar:           jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error { return errStop },
+	}, nil, WithClientPerWorker(3))
+
+	seen := make(map[*http.Transport]bool)
+	for range 3 {
+		client := <-c.clients
+		if client.Jar != jar {
+			t.Error("cookie jar dropped")
+		}
+		if client.CheckRedirect == nil || client.CheckRedirect(nil, nil) != errStop {
+			t.Error("CheckRedirect dropped")
+		}
+		tr, ok := client.Transport.(*http.Transport)
+		if !ok || tr == injected || seen[tr] || tr.MaxConnsPerHost != 7 {
+			t.Errorf("transport %p is not a fresh clone of the injected one", client.Transport)
+		}
+		seen[tr] = true
+	}
+}
+
+func TestClientPerWorkerWithCustomTransport(t *testing.T) {
+	srv := newRatingServer(t, samplePayload)
+	tracker := &closeTracker{next: srv.Client().Transport}
+	c := NewCrawler(&http.Client{Transport: tracker}, nil, WithClientPerWorker(4))
+	if c.clients != nil {
+		t.Fatal("per-worker clients built around a custom transport")
+	}
+	if _, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1"); err != nil {
+		t.Fatal(err)
+	}
+	if n := tracker.opened.Load(); n != 1 {
+		t.Errorf("custom transport made %d requests, want 1", n)
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	var proxied atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.URL.String())
+		w.Write([]byte(samplePayload))
+	}))
+	defer proxy.Close()
+
+	const target = "http://enroll.invalid/rating?directionId=1"
+	c := NewCrawler(&http.Client{}, nil, WithProxy(proxy.URL))
+	if _, err := c.GetCompetitionList(context.Background(), target); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := proxied.Load().(string); got != target {
+		t.Errorf("proxy got %q, want %q", got, target)
+	}
+}
+
+func TestWithInvalidProxy(t *testing.T) {
+	c := NewCrawler(&http.Client{}, nil, WithProxy("not a proxy"))
+	_, err := c.GetCompetitionList(context.Background(), "http://enroll.invalid/rating?directionId=1")
+	if err == nil || !strings.Contains(err.Error(), "invalid proxy") {
+		t.Errorf("err = %v, want the proxy error", err)
+	}
+}
+
+func TestWithTLSConfigTrustsCustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(samplePayload))
+	}))
+	defer srv.Close()
+	url := srv.URL + "?directionId=1"
+
+	// The server certificate is not trusted by the system roots.
+	if _, err := NewCrawler(&http.Client{}, nil).GetCompetitionList(context.Background(), url); err == nil {
+		t.Fatal("want a certificate error without the test CA")
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	for _, opts := range [][]Option{
+		{WithTLSConfig(&tls.Config{RootCAs: roots})},
+		{WithTLSConfig(&tls.Config{RootCAs: roots}), WithClientPerWorker(2)},
+	} {
+		c := NewCrawler(&http.Client{}, nil, opts...)
+		if _, err := c.GetCompetitionList(context.Background(), url); err != nil {
+			t.Errorf("with %d options: %v", len(opts), err)
+		}
+	}
+}
+
+// BenchmarkClients crawls a local server from GOMAXPROCS goroutines, either
+// through http.DefaultTransport or with a tuned transport per worker. The
+// default transport keeps only two idle connections per host, so with more
+// workers most requests dial a new connection, which per-worker clients
+// avoid on a machine with several cores.
+func BenchmarkClients(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(samplePayload))
+	}))
+	defer srv.Close()
+	url := srv.URL + "?directionId=1"
+
+	for _, bc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"shared", nil},
+		{"per-worker", []Option{WithClientPerWorker(runtime.GOMAXPROCS(0))}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			c := NewCrawler(&http.Client{}, nil, bc.opts...)
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := c.GetCompetitionList(context.Background(), url); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"slices"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// WithCoalescing makes concurrent requests for the same URL share a single
+// HTTP call. The shared call runs with the context of the request that
+// started it, so if that one is cancelled the others fail along with it.
+func WithCoalescing() Option {
+	return func(c *Crawler) {
+		c.flight = &singleflight.Group{}
+	}
+}
+
+func (c *Crawler) fetchCoalesced(ctx context.Context, url string) (*Response, error) {
+	if c.flight == nil {
+		return c.fetchRateLimited(ctx, url)
+	}
+	v, err, shared := c.flight.Do(url, func() (any, error) {
+		return c.fetchRateLimited(ctx, url)
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := v.(*Response)
+	if shared {
+		// Every caller filters the users of its response in place.
+		dup := *resp
+		dup.Users = slices.Clone(resp.Users)
+		resp = &dup
+	}
+	return resp, nil
+}
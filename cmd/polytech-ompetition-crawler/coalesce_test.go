@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCoalescingSharesOneCall(t *testing.T) {
+	arrived, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() { close(arrived) })
+		<-release
+		w.Write([]byte(samplePayload))
+	}))
+	defer srv.Close()
+	tracker := &closeTracker{next: srv.Client().Transport}
+	c := NewCrawler(&http.Client{Transport: tracker}, nil, WithCoalescing())
+
+	var wg sync.WaitGroup
+	results := make([][]User, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			users, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1")
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = users
+		}()
+	}
+	// Hold the first request until the other callers have joined it.
+	<-arrived
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := tracker.opened.Load(); n != 1 {
+		t.Errorf("RoundTripper invoked %d times, want 1", n)
+	}
+	for i, users := range results {
+		if len(users) != 2 {
+			t.Errorf("caller %d got %d users", i, len(users))
+		}
+	}
+	if &results[0][0] == &results[1][0] {
+		t.Error("callers share the same users slice")
+	}
+}
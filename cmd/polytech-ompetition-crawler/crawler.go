@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	defaultHeaders = map[string]string{
+		"User-Agent":      "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0",
+		"Accept":          "application/json",
+		"Accept-Language": "en-US,en;q=0.5",
+	}
+
+	defaultCrawler = NewCrawler(http.DefaultClient, nil)
+)
+
+// ErrNoUsers is returned for a direction whose list comes back empty while
+// its total claims applicants, which the site does for directions that are
+// not open. Scanning a wide direction ID range hits many of those, so
+// callers usually skip it. A direction legitimately without applicants,
+// with a zero total, is returned as an empty list instead.
+var ErrNoUsers = errors.New("have not users for this directionId")
+
+// defaultMaxResponseSize is far above the size of the largest directions.
+const defaultMaxResponseSize = 32 << 20
+
+// ErrResponseTooLarge is returned for a response body, after decompression,
+// larger than the limit set with WithMaxResponseSize.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// StatusError is returned when the enrollment server answers with a non-2xx status.
+type StatusError struct {
+	StatusCode int
+	URL        string
+	// RetryAfter is the delay requested by the Retry-After header, if any.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d for direction %s", e.StatusCode, e.URL)
+}
+
+// DecodeError is returned when a response body is not a rating list.
+type DecodeError struct {
+	URL string
+	Err error
+	// BodySnippet holds the first bytes of the body.
+	BodySnippet []byte
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode response of %s: %v", e.URL, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Crawler fetches competition lists through its own http.Client.
+type Crawler struct {
+	client  *http.Client
+	headers map[string]string
+	limiter *rateLimiter
+	logger  *slog.Logger
+	decoder Decoder
+	breaker *circuitBreaker
+	retries *retryBudget
+	metrics *Metrics
+	cache   *responseCache
+	flight  *singleflight.Group
+
+	level EducationLevel
+	form  EducationFormId
+
+	validateLevel bool
+	minScore      uint16
+
+	maxResponseSize int64
+	dumpDir         string
+	pingDirection   uint64
+
+	shuffle     bool
+	shuffleSeed int64
+
+	perWorker int
+	clients   chan *http.Client
+	proxy     func(*http.Request) (*url.URL, error)
+	tlsConfig *tls.Config
+}
+
+// Option configures optional Crawler behaviour.
+type Option func(*Crawler)
+
+// WithRateLimit limits the crawler to rps requests per second across
+// all goroutines sharing it. Non-positive values disable the limit.
+func WithRateLimit(rps float64) Option {
+	return func(c *Crawler) {
+		if rps > 0 {
+			c.limiter = newRateLimiter(rps)
+		} else {
+			c.limiter = nil
+		}
+	}
+}
+
+// WithMaxResponseSize limits the size of a response body to n bytes, 32 MiB
+// by default, so that a broken server cannot exhaust the memory. Larger
+// bodies fail with ErrResponseTooLarge.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *Crawler) {
+		c.maxResponseSize = n
+	}
+}
+
+// WithLogger makes the crawler report request events to logger.
+// By default nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Crawler) {
+		c.logger = logger
+	}
+}
+
+// WithHeaders sets request headers on top of the defaults, replacing
+// defaults with the same name. An empty value removes the header.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Crawler) {
+		for k, v := range headers {
+			if v == "" {
+				delete(c.headers, k)
+			} else {
+				c.headers[k] = v
+			}
+		}
+	}
+}
+
+// NewCrawler creates a Crawler sending the default headers merged with the
+// given ones (see WithHeaders) with every request. A nil client falls back to
+// http.DefaultClient.
+func NewCrawler(client *http.Client, headers map[string]string, opts ...Option) *Crawler {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	c := &Crawler{
+		client:  client,
+		headers: maps.Clone(defaultHeaders),
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		decoder: jsonDecoder{},
+		level:   EducationLevelMaster,
+		form:    EducationFormIdFullTime,
+
+		maxResponseSize: defaultMaxResponseSize,
+		pingDirection:   firstDirID,
+	}
+	WithHeaders(headers)(c)
+	for _, opt := range opts {
+		opt(c)
+	}
+	// Before applyTransportOptions, which replaces a nil transport.
+	c.buildWorkerClients()
+	c.applyTransportOptions()
+	return c
+}
+
+func GetCompetitionList(ctx context.Context, url string) ([]User, error) {
+	return defaultCrawler.GetCompetitionList(ctx, url)
+}
+
+func GetCompetitionListFull(ctx context.Context, url string) (*Response, error) {
+	return defaultCrawler.GetCompetitionListFull(ctx, url)
+}
+
+func (c *Crawler) GetCompetitionList(ctx context.Context, url string) ([]User, error) {
+	resp, err := c.GetCompetitionListFull(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Users, nil
+}
+
+// GetCompetitionListFull returns the whole decoded response including
+// direction capacity, applicant counters, the direction title and the time
+// of the last list update.
+func (c *Crawler) GetCompetitionListFull(ctx context.Context, url string) (*Response, error) {
+	resp, err := c.fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return c.postProcess(ctx, url, resp)
+}
+
+// postProcess applies the configured user filters to a complete direction
+// list. It fails with ErrNoUsers on an empty list whose total is not zero;
+// a list emptied by the filters is returned as is.
+func (c *Crawler) postProcess(ctx context.Context, url string, resp *Response) (*Response, error) {
+	if len(resp.Users) == 0 {
+		if resp.Total > 0 {
+			c.logger.InfoContext(ctx, "direction has no users", "direction", directionID(url), "total", resp.Total)
+			return nil, ErrNoUsers
+		}
+		return resp, nil
+	}
+	if c.validateLevel {
+		resp.Users = c.dropOtherLevels(ctx, url, resp.Users)
+	}
+	if c.minScore > 0 {
+		resp.Users = FilterUsers(resp.Users, ScoreAtLeast(c.minScore))
+	}
+	return resp, nil
+}
+
+// fetch makes a single request and decodes the response as is.
+func (c *Crawler) fetch(ctx context.Context, url string) (*Response, error) {
+	if c.cache != nil {
+		if resp, ok := c.cache.get(url); ok {
+			return resp, nil
+		}
+	}
+	resp, err := c.fetchCoalesced(ctx, url)
+	if err == nil && c.cache != nil {
+		c.cache.put(url, resp)
+	}
+	return resp, err
+}
+
+func (c *Crawler) fetchGuarded(ctx context.Context, url string) (*Response, error) {
+	if c.breaker == nil {
+		return c.doFetch(ctx, url)
+	}
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	resp, err := c.doFetch(ctx, url)
+	c.breaker.record(err != nil && isRetryable(err), err != nil && ctx.Err() != nil)
+	return resp, err
+}
+
+func (c *Crawler) doFetch(ctx context.Context, url string) (*Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	c.logger.DebugContext(ctx, "request started", "direction", directionID(url), "url", url)
+	client, release, err := c.acquireClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		c.observeRequest(start, 0)
+		c.logger.WarnContext(ctx, "request failed", "direction", directionID(url), "error", err)
+		return nil, err
+	}
+	c.observeRequest(start, resp.StatusCode)
+	defer drainAndClose(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		c.logger.WarnContext(ctx, "request failed", "direction", directionID(url), "status", resp.StatusCode)
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			URL:        url,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	r, err := decodedBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(io.LimitReader(r, c.maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.maxResponseSize {
+		return nil, fmt.Errorf("%w: more than %d bytes from %s", ErrResponseTooLarge, c.maxResponseSize, url)
+	}
+	c.dump(ctx, url, body)
+	if err := checkJSONObject(body); err != nil {
+		return nil, &DecodeError{URL: url, Err: err, BodySnippet: bodySnippet(body)}
+	}
+	var resultResp Response
+	if err = c.decoder.Decode(body, &resultResp); err != nil {
+		return nil, &DecodeError{URL: url, Err: err, BodySnippet: bodySnippet(body)}
+	}
+	// Users is only nil when the "list" key is missing (or null), which
+	// means the body is not a rating page.
+	if resultResp.Users == nil {
+		return nil, &DecodeError{
+			URL:         url,
+			Err:         fmt.Errorf("unexpected JSON response without \"list\", first bytes: %q", bodySnippet(body)),
+			BodySnippet: bodySnippet(body),
+		}
+	}
+	return &resultResp, nil
+}
+
+// snippetSize is how much of an unexpected body goes into the error.
+const snippetSize = 64
+
+// checkJSONObject rejects bodies that are not a JSON object, such as the
+// HTML error pages served by proxies, before they reach the decoder.
+func checkJSONObject(body []byte) error {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return fmt.Errorf("unexpected non-JSON response, first bytes: %q", bodySnippet(body))
+	}
+	return nil
+}
+
+func bodySnippet(body []byte) []byte {
+	if len(body) > snippetSize {
+		return body[:snippetSize]
+	}
+	return body
+}
+
+// directionID extracts the directionId query parameter for log records.
+func directionID(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("directionId")
+}
+
+// drainAndClose reads the rest of the body before closing it so that
+// the keep-alive connection goes back to the transport pool.
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(io.Discard, body)
+	body.Close()
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// samplePayload is a trimmed response of the form-rating endpoint.
+const samplePayload = `{
+	"directionCapacity": 25,
+	"total": 2,
+	"totalWithOriginals": 1,
+	"list": [
+		{"userSnils": "111-111-111 11", "userUniqueId": "a", "fullScore": 270, "priority": 1, "hasOriginalDocuments": true},
+		{"userSnils": "222-222-222 22", "userUniqueId": "b", "fullScore": 250, "priority": 2}
+	]
+}`
+
+// newRatingServer serves body as JSON for every request.
+func newRatingServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetCompetitionListFullDecodesCounters(t *testing.T) {
+	srv := newRatingServer(t, samplePayload)
+	c := NewCrawler(srv.Client(), nil)
+
+	resp, err := c.GetCompetitionListFull(context.Background(), srv.URL+"?directionId=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.DirectionCapacity != 25 || resp.Total != 2 || resp.TotalWithOriginals != 1 {
+		t.Errorf("counters = %d, %d, %d; want 25, 2, 1", resp.DirectionCapacity, resp.Total, resp.TotalWithOriginals)
+	}
+	if len(resp.Users) != 2 || resp.Users[0].UserSnils != "111-111-111 11" {
+		t.Errorf("users = %+v", resp.Users)
+	}
+}
+
+func TestGetCompetitionListStatusError(t *testing.T) {
+	for _, code := range []int{http.StatusInternalServerError, http.StatusNotFound} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "<html>oops</html>", code)
+		}))
+		c := NewCrawler(srv.Client(), nil)
+		_, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=7")
+		srv.Close()
+
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != code {
+			t.Errorf("status %d: err = %v, want a StatusError", code, err)
+			continue
+		}
+		if msg := err.Error(); !strings.Contains(msg, strconv.Itoa(code)) {
+			t.Errorf("status %d: message %q lacks the code", code, msg)
+		}
+	}
+}
+
+// closeTracker is a RoundTripper counting response bodies that are opened
+// and closed.
+type closeTracker struct {
+	next           http.RoundTripper
+	opened, closed atomic.Int64
+}
+
+func (ct *closeTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := ct.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	ct.opened.Add(1)
+	resp.Body = &trackedBody{ReadCloser: resp.Body, closed: &ct.closed}
+	return resp, nil
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	closed *atomic.Int64
+	once   sync.Once
+}
+
+func (b *trackedBody) Close() error {
+	b.once.Do(func() { b.closed.Add(1) })
+	return b.ReadCloser.Close()
+}
+
+func TestResponseBodiesAreClosed(t *testing.T) {
+	ok := newRatingServer(t, samplePayload)
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	tracker := &closeTracker{next: http.DefaultTransport}
+	c := NewCrawler(&http.Client{Transport: tracker}, nil)
+	for range 50 {
+		if _, err := c.GetCompetitionList(context.Background(), ok.URL+"?directionId=1"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.GetCompetitionList(context.Background(), failing.URL+"?directionId=2"); err == nil {
+			t.Fatal("want an error for 502")
+		}
+	}
+	if opened, closed := tracker.opened.Load(), tracker.closed.Load(); opened != 100 || closed != opened {
+		t.Errorf("opened %d bodies, closed %d", opened, closed)
+	}
+}
+
+func TestCrawlerUsesInjectedClient(t *testing.T) {
+	var gotHeader atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader.Store(r.Header.Get("X-Test"))
+		w.Write([]byte(samplePayload))
+	}))
+	defer srv.Close()
+
+	tracker := &closeTracker{next: srv.Client().Transport}
+	c := NewCrawler(&http.Client{Transport: tracker}, map[string]string{"X-Test": "injected"})
+	if _, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1"); err != nil {
+		t.Fatal(err)
+	}
+	if n := tracker.opened.Load(); n != 1 {
+		t.Errorf("injected client made %d requests, want 1", n)
+	}
+	if h, _ := gotHeader.Load().(string); h != "injected" {
+		t.Errorf("X-Test header = %q", h)
+	}
+}
+
+func TestEmptyDirectionReturnsErrNoUsers(t *testing.T) {
+	srv := newRatingServer(t, `{"total": 5, "list": []}`)
+	c := NewCrawler(srv.Client(), nil)
+	users, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1")
+	if !errors.Is(err, ErrNoUsers) {
+		t.Errorf("err = %v, want ErrNoUsers", err)
+	}
+	if users != nil {
+		t.Errorf("users = %v, want nil", users)
+	}
+}
+
+func TestZeroTotalDirectionIsEmpty(t *testing.T) {
+	srv := newRatingServer(t, `{"total": 0, "list": []}`)
+	c := NewCrawler(srv.Client(), nil)
+	users, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1")
+	if err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+	if len(users) != 0 {
+		t.Errorf("users = %v, want none", users)
+	}
+}
+
+func TestMissingDirectionIsError(t *testing.T) {
+	srv := newRatingServer(t, `{"total": 0}`)
+	c := NewCrawler(srv.Client(), nil)
+	if users, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1"); err == nil {
+		t.Errorf("users = %v, want an error", users)
+	}
+}
+
+// headerRecorder is a RoundTripper keeping the headers of the last request.
+type headerRecorder struct {
+	next   http.RoundTripper
+	mu     sync.Mutex
+	header http.Header
+}
+
+func (hr *headerRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	hr.mu.Lock()
+	hr.header = req.Header.Clone()
+	hr.mu.Unlock()
+	return hr.next.RoundTrip(req)
+}
+
+func TestHeadersMergeOntoDefaults(t *testing.T) {
+	srv := newRatingServer(t, samplePayload)
+	rec := &headerRecorder{next: srv.Client().Transport}
+	c := NewCrawler(&http.Client{Transport: rec}, map[string]string{
+		"User-Agent":      "crawler-test",
+		"X-Token":         "secret",
+		"Accept-Language": "",
+	})
+	if _, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1"); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"User-Agent":      "crawler-test",
+		"X-Token":         "secret",
+		"Accept":          defaultHeaders["Accept"],
+		"Accept-Language": "",
+	} {
+		if got := rec.header.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestHTMLPageFailsWithDescriptiveError(t *testing.T) {
+	srv := newRatingServer(t, "<!DOCTYPE html><html><body>Service Unavailable</body></html>")
+	c := NewCrawler(srv.Client(), nil)
+	_, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("err = %v, want a DecodeError", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "unexpected non-JSON response, first bytes:") || !strings.Contains(msg, "<!DOCTYPE html>") {
+		t.Errorf("message = %q", msg)
+	}
+}
+
+func TestObjectWithoutListIsRejected(t *testing.T) {
+	srv := newRatingServer(t, `{"error": "maintenance"}`)
+	c := NewCrawler(srv.Client(), nil)
+	_, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1")
+	if err == nil || !strings.Contains(err.Error(), `without "list"`) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestDecodeErrorCarriesURL(t *testing.T) {
+	srv := newRatingServer(t, `{"list": [{"fullScore": "many"}]}`)
+	c := NewCrawler(srv.Client(), nil)
+	url := srv.URL + "?directionId=9"
+	_, err := c.GetCompetitionList(context.Background(), url)
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("err = %v, want a DecodeError", err)
+	}
+	if decodeErr.URL != url || directionID(decodeErr.URL) != "9" {
+		t.Errorf("URL = %q", decodeErr.URL)
+	}
+	if !strings.HasPrefix(string(decodeErr.BodySnippet), `{"list"`) {
+		t.Errorf("snippet = %q", decodeErr.BodySnippet)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Errorf("err = %v does not unwrap to the json error", err)
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"list": [`))
+		for range 1000 {
+			w.Write([]byte(`{"userSnils": "111-111-111 11"},`))
+		}
+		w.Write([]byte(`{}]}`))
+	}))
+	defer srv.Close()
+	url := srv.URL + "?directionId=1"
+
+	_, err := NewCrawler(srv.Client(), nil, WithMaxResponseSize(1024)).GetCompetitionList(context.Background(), url)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("err = %v, want ErrResponseTooLarge", err)
+	}
+	if _, err := NewCrawler(srv.Client(), nil).GetCompetitionList(context.Background(), url); err != nil {
+		t.Errorf("default limit: %v", err)
+	}
+}
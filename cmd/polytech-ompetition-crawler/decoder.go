@@ -0,0 +1,27 @@
+package main
+
+import "encoding/json"
+
+// Decoder decodes a rating response body into v, a *Response. It lets a
+// faster JSON implementation replace encoding/json for large directions.
+type Decoder interface {
+	Decode(data []byte, v any) error
+}
+
+// jsonDecoder is the default Decoder backed by encoding/json.
+type jsonDecoder struct{}
+
+func (jsonDecoder) Decode(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// WithDecoder makes the Crawler decode responses with d. A nil d restores
+// the encoding/json default.
+func WithDecoder(d Decoder) Option {
+	return func(c *Crawler) {
+		if d == nil {
+			d = jsonDecoder{}
+		}
+		c.decoder = d
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// countingDecoder is a Decoder counting its calls.
+type countingDecoder struct {
+	calls atomic.Int64
+}
+
+func (d *countingDecoder) Decode(data []byte, v any) error {
+	d.calls.Add(1)
+	return json.Unmarshal(data, v)
+}
+
+func TestWithDecoderIsUsed(t *testing.T) {
+	srv := newRatingServer(t, samplePayload)
+	d := &countingDecoder{}
+	c := NewCrawler(srv.Client(), nil, WithDecoder(d))
+	users, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || d.calls.Load() != 1 {
+		t.Errorf("decoded %d users with %d decoder calls", len(users), d.calls.Load())
+	}
+	if _, ok := NewCrawler(nil, nil, WithDecoder(nil)).decoder.(jsonDecoder); !ok {
+		t.Error("WithDecoder(nil) does not restore the default")
+	}
+}
+
+// streamDecoder decodes through a json.Decoder instead of json.Unmarshal.
+type streamDecoder struct{}
+
+func (streamDecoder) Decode(data []byte, v any) error {
+	return json.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// largePayload returns a rating of n applicants with all the fields the
+// site sends.
+func largePayload(n int) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, `{"directionCapacity": 120, "total": %d, "totalWithOriginals": %d, "list": [`, n, n/3)
+	for i := range n {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"applicationEducationLevel": "MASTER", "directionId": 250,
+			"directionEducationForm": {"id": 2, "title": "Очная", "externalId": "f2"},
+			"subjects": [{"title": "Информатика", "externalId": "s1", "score": %d}],
+			"userFullName": "Иванов И***", "userSnils": "%03d-000-000 00", "userUniqueId": "u%d",
+			"priority": %d, "fullScore": %d, "subjectScore": %d, "hasOriginalDocuments": %t,
+			"certificateAverage": 4.5, "state": "ACTIVE"}`, i%100, i, i, i%5+1, 300-i%300, i%100, i%3 == 0)
+	}
+	b.WriteString("]}")
+	return []byte(b.String())
+}
+
+// BenchmarkDecoders decodes a direction of 5000 applicants with the default
+// decoder and with a streaming one, as a baseline for plugging in others.
+func BenchmarkDecoders(b *testing.B) {
+	payload := largePayload(5000)
+	for _, bc := range []struct {
+		name string
+		d    Decoder
+	}{
+		{"unmarshal", jsonDecoder{}},
+		{"stream", streamDecoder{}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.SetBytes(int64(len(payload)))
+			b.ReportAllocs()
+			for range b.N {
+				var resp Response
+				if err := bc.d.Decode(payload, &resp); err != nil {
+					b.Fatal(err)
+				}
+				if len(resp.Users) != 5000 {
+					b.Fatalf("decoded %d users", len(resp.Users))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Change describes one applicant entry in one direction between two
+// snapshots. Old* fields are zero for added entries, New* for removed ones.
+type Change struct {
+	Snils       Snils
+	DirectionId uint64
+
+	OldPosition     uint64
+	NewPosition     uint64
+	OldScore        uint16
+	NewScore        uint16
+	OldHasOriginals bool
+	NewHasOriginals bool
+	OldState        string
+	NewState        string
+}
+
+// Moved reports whether the applicant changed position in the list.
+func (c Change) Moved() bool {
+	return c.OldPosition != c.NewPosition
+}
+
+type SnapshotDiff struct {
+	Added   []Change
+	Removed []Change
+	Changed []Change
+}
+
+// DiffSnapshots compares two crawl results entry by entry, an entry being
+// an applicant in a direction. Entries are sorted by SNILS and direction.
+func DiffSnapshots(oldDb, newDb UserDb) SnapshotDiff {
+	type key struct {
+		snils Snils
+		dir   uint64
+	}
+	index := func(db UserDb) map[key]UserInfo {
+		m := make(map[key]UserInfo)
+		for snils, row := range db {
+			for _, info := range row {
+				m[key{snils, info.u.DirectionId}] = info
+			}
+		}
+		return m
+	}
+	oldEntries, newEntries := index(oldDb), index(newDb)
+
+	var diff SnapshotDiff
+	for k, n := range newEntries {
+		c := Change{
+			Snils:           k.snils,
+			DirectionId:     k.dir,
+			NewPosition:     n.position,
+			NewScore:        n.u.FullScore,
+			NewHasOriginals: n.u.HasOriginalDocuments,
+			NewState:        n.u.State,
+		}
+		o, ok := oldEntries[k]
+		if !ok {
+			diff.Added = append(diff.Added, c)
+			continue
+		}
+		c.OldPosition = o.position
+		c.OldScore = o.u.FullScore
+		c.OldHasOriginals = o.u.HasOriginalDocuments
+		c.OldState = o.u.State
+		if c.OldPosition != c.NewPosition || c.OldScore != c.NewScore ||
+			c.OldHasOriginals != c.NewHasOriginals || c.OldState != c.NewState {
+			diff.Changed = append(diff.Changed, c)
+		}
+	}
+	for k, o := range oldEntries {
+		if _, ok := newEntries[k]; !ok {
+			diff.Removed = append(diff.Removed, Change{
+				Snils:           k.snils,
+				DirectionId:     k.dir,
+				OldPosition:     o.position,
+				OldScore:        o.u.FullScore,
+				OldHasOriginals: o.u.HasOriginalDocuments,
+				OldState:        o.u.State,
+			})
+		}
+	}
+
+	for _, changes := range [][]Change{diff.Added, diff.Removed, diff.Changed} {
+		slices.SortFunc(changes, compareChanges)
+	}
+	return diff
+}
+
+func compareChanges(a, b Change) int {
+	if c := cmp.Compare(a.Snils, b.Snils); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.DirectionId, b.DirectionId)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func dbOf(infos ...UserInfo) UserDb {
+	db := make(UserDb)
+	for _, info := range infos {
+		db.addUserRow(info)
+	}
+	return db
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	oldDb := dbOf(
+		UserInfo{position: 1, u: &User{UserSnils: "1", DirectionId: 200, FullScore: 270}},
+		UserInfo{position: 2, u: &User{UserSnils: "2", DirectionId: 200, FullScore: 260}},
+		UserInfo{position: 3, u: &User{UserSnils: "3", DirectionId: 200, FullScore: 250}},
+	)
+	newDb := dbOf(
+		UserInfo{position: 1, u: &User{UserSnils: "1", DirectionId: 200, FullScore: 270}},
+		UserInfo{position: 3, u: &User{UserSnils: "2", DirectionId: 200, FullScore: 260}},
+		UserInfo{position: 2, u: &User{UserSnils: "4", DirectionId: 200, FullScore: 265, HasOriginalDocuments: true}},
+	)
+
+	diff := DiffSnapshots(oldDb, newDb)
+	want := SnapshotDiff{
+		Added:   []Change{{Snils: "4", DirectionId: 200, NewPosition: 2, NewScore: 265, NewHasOriginals: true}},
+		Removed: []Change{{Snils: "3", DirectionId: 200, OldPosition: 3, OldScore: 250}},
+		Changed: []Change{{Snils: "2", DirectionId: 200, OldPosition: 2, NewPosition: 3, OldScore: 260, NewScore: 260}},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("diff = %+v, want %+v", diff, want)
+	}
+	if !diff.Changed[0].Moved() {
+		t.Error("position change is not reported as moved")
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// WithResponseDump saves the raw body of every response read by the
+// Crawler to dir, overwriting earlier dumps of the same request. Files are
+// named <directionID>[-form<form>][-page<page>].json after the query of the
+// request, so that the forms and pages of one direction are kept apart.
+// Bodies failing to decode are dumped as well, which is what the dumps are
+// usually wanted for. dir must exist.
+func WithResponseDump(dir string) Option {
+	return func(c *Crawler) {
+		c.dumpDir = dir
+	}
+}
+
+func (c *Crawler) dump(ctx context.Context, rawURL string, body []byte) {
+	name := dumpName(rawURL)
+	if c.dumpDir == "" || name == "" {
+		return
+	}
+	path := filepath.Join(c.dumpDir, name)
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		c.logger.WarnContext(ctx, "response dump failed", "direction", directionID(rawURL), "error", err)
+	}
+}
+
+// dumpName returns the dump file name for rawURL, empty without a direction.
+func dumpName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	name := filepath.Base(q.Get("directionId"))
+	if name == "." || name == string(filepath.Separator) {
+		return ""
+	}
+	if form := q.Get("directioneducationformid"); form != "" {
+		name += "-form" + filepath.Base(form)
+	}
+	if page := q.Get("page"); page != "" {
+		name += "-page" + filepath.Base(page)
+	}
+	return name + ".json"
+}
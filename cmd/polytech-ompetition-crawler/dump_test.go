@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDumpName(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"http://host/list?directionId=7", "7.json"},
+		{"http://host/list?directionId=7&directioneducationformid=2", "7-form2.json"},
+		{"http://host/list?directionId=7&directioneducationformid=2&page=3", "7-form2-page3.json"},
+		{"http://host/list?directionId=../../etc&page=../x", "etc-pagex.json"},
+		{"http://host/list?page=1", ""},
+	}
+	for _, tt := range tests {
+		if got := dumpName(tt.url); got != tt.want {
+			t.Errorf("dumpName(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestResponseDumpKeepsFormsApart(t *testing.T) {
+	srv := newRatingServer(t, samplePayload)
+	dir := t.TempDir()
+	c := NewCrawler(srv.Client(), nil, WithResponseDump(dir))
+
+	for _, query := range []string{
+		"?directionId=1&directioneducationformid=1",
+		"?directionId=1&directioneducationformid=2",
+		"?directionId=1&directioneducationformid=2&page=1",
+	} {
+		if _, err := c.GetCompetitionListFull(context.Background(), srv.URL+query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	want := []string{"1-form1.json", "1-form2-page1.json", "1-form2.json"}
+	if !slices.Equal(names, want) {
+		t.Errorf("dumps = %v, want %v", names, want)
+	}
+	body, err := os.ReadFile(filepath.Join(dir, want[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != samplePayload {
+		t.Errorf("dump = %q, want the response body", body)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// compressedServer serves samplePayload compressed with encoding, whatever
+// the request asked for.
+func compressedServer(t *testing.T, encoding string) *httptest.Server {
+	t.Helper()
+	var buf bytes.Buffer
+	var zw io.WriteCloser
+	switch encoding {
+	case "gzip":
+		zw = gzip.NewWriter(&buf)
+	case "deflate":
+		zw = zlib.NewWriter(&buf)
+	}
+	zw.Write([]byte(samplePayload))
+	zw.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", encoding)
+		w.Write(buf.Bytes())
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCompressedResponses(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate"} {
+		srv := compressedServer(t, encoding)
+		// An explicit Accept-Encoding turns off the transport decompression.
+		for _, headers := range []map[string]string{nil, {"Accept-Encoding": encoding}} {
+			c := NewCrawler(srv.Client(), headers)
+			users, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1")
+			if err != nil {
+				t.Errorf("%s, headers %v: %v", encoding, headers, err)
+				continue
+			}
+			if len(users) != 2 {
+				t.Errorf("%s, headers %v: got %d users", encoding, headers, len(users))
+			}
+		}
+	}
+}
+
+func TestUnsupportedEncoding(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		w.Write([]byte("compressed"))
+	}))
+	defer srv.Close()
+	if _, err := NewCrawler(srv.Client(), nil).GetCompetitionList(context.Background(), srv.URL); err == nil {
+		t.Error("want an error for an unsupported encoding")
+	}
+}
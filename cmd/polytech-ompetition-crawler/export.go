@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+var csvHeader = []string{"snils", "direction_id", "specialty", "full_score", "priority", "position", "has_original_documents"}
+
+// WriteCSV writes one row per UserInfo, applicants ordered by SNILS.
+func WriteCSV(w io.Writer, db UserDb) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, snils := range db.sortedSnils() {
+		for _, info := range db[snils] {
+			if err := cw.Write(exportRow(snils, info)); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportRow formats an entry as the csvHeader columns.
+func exportRow(snils Snils, info UserInfo) []string {
+	return []string{
+		string(snils),
+		strconv.FormatUint(info.u.DirectionId, 10),
+		info.specialty(),
+		strconv.FormatUint(uint64(info.u.FullScore), 10),
+		strconv.FormatUint(uint64(info.u.Priority), 10),
+		strconv.FormatUint(info.position, 10),
+		strconv.FormatBool(info.u.HasOriginalDocuments),
+	}
+}
+
+// WriteTable writes db as a plain text table with the columns of WriteCSV,
+// aligned for reading in a terminal.
+func WriteTable(w io.Writer, db UserDb) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(csvHeader, "\t"))
+	for _, snils := range db.sortedSnils() {
+		for _, info := range db[snils] {
+			fmt.Fprintln(tw, strings.Join(exportRow(snils, info), "\t"))
+		}
+	}
+	return tw.Flush()
+}
+
+// DirectionStats holds the per-direction counters of a Response.
+type DirectionStats struct {
+	DirectionCapacity  uint64 `json:"directionCapacity"`
+	Total              uint64 `json:"total"`
+	TotalWithOriginals uint64 `json:"totalWithOriginals"`
+}
+
+func (r *Response) DirectionStats() DirectionStats {
+	return DirectionStats{
+		DirectionCapacity:  r.DirectionCapacity,
+		Total:              r.Total,
+		TotalWithOriginals: r.TotalWithOriginals,
+	}
+}
+
+type jsonEntry struct {
+	DirectionId          uint64 `json:"directionId"`
+	Specialty            string `json:"specialty"`
+	FullScore            uint16 `json:"fullScore"`
+	Priority             uint16 `json:"priority"`
+	Position             uint64 `json:"position"`
+	HasOriginalDocuments bool   `json:"hasOriginalDocuments"`
+}
+
+type jsonDocument struct {
+	Directions map[uint64]DirectionStats `json:"directions,omitempty"`
+	Users      map[Snils][]jsonEntry     `json:"users"`
+}
+
+func newJSONEntry(info UserInfo) jsonEntry {
+	return jsonEntry{
+		DirectionId:          info.u.DirectionId,
+		Specialty:            info.specialty(),
+		FullScore:            info.u.FullScore,
+		Priority:             info.u.Priority,
+		Position:             info.position,
+		HasOriginalDocuments: info.u.HasOriginalDocuments,
+	}
+}
+
+// WriteJSON writes db as an indented JSON document keyed by SNILS. Counters
+// of the crawled directions are included when directions is not empty.
+// Object keys are sorted, so the output is stable between runs.
+func WriteJSON(w io.Writer, db UserDb, directions map[uint64]DirectionStats) error {
+	doc := jsonDocument{
+		Directions: directions,
+		Users:      make(map[Snils][]jsonEntry, len(db)),
+	}
+	for snils, row := range db {
+		entries := make([]jsonEntry, 0, len(row))
+		for _, info := range row {
+			entries = append(entries, newJSONEntry(info))
+		}
+		doc.Users[snils] = entries
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func (db UserDb) sortedSnils() []Snils {
+	keys := make([]Snils, 0, len(db))
+	for snils := range db {
+		keys = append(keys, snils)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sampleDb holds two applicants, the first with entries in two directions.
+func sampleDb() UserDb {
+	db := make(UserDb)
+	for _, info := range []UserInfo{
+		{position: 1, u: &User{UserSnils: "222", DirectionId: 201, FullScore: 250, Priority: 2, Subjects: []Subject{{Title: "Физика"}}}},
+		{position: 3, u: &User{UserSnils: "111", DirectionId: 200, FullScore: 270, Priority: 1, HasOriginalDocuments: true}},
+		{position: 2, u: &User{UserSnils: "111", DirectionId: 201, FullScore: 270, Priority: 3}},
+	} {
+		db.addUserRow(info)
+	}
+	return db
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, sampleDb()); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		csvHeader,
+		{"111", "200", "", "270", "1", "3", "true"},
+		{"111", "201", "", "270", "3", "2", "false"},
+		{"222", "201", "Физика", "250", "2", "1", "false"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+}
+
+func TestWriteJSONRoundTrip(t *testing.T) {
+	directions := map[uint64]DirectionStats{200: {DirectionCapacity: 25, Total: 2, TotalWithOriginals: 1}}
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, sampleDb(), directions); err != nil {
+		t.Fatal(err)
+	}
+	var doc jsonDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(doc.Directions, directions) {
+		t.Errorf("directions = %+v", doc.Directions)
+	}
+	want := []jsonEntry{
+		{DirectionId: 200, FullScore: 270, Priority: 1, Position: 3, HasOriginalDocuments: true},
+		{DirectionId: 201, FullScore: 270, Priority: 3, Position: 2},
+	}
+	if !reflect.DeepEqual(doc.Users["111"], want) {
+		t.Errorf("users[111] = %+v, want %+v", doc.Users["111"], want)
+	}
+	if len(doc.Users) != 2 {
+		t.Errorf("got %d users, want 2", len(doc.Users))
+	}
+}
+
+func TestWriteJSONIsStable(t *testing.T) {
+	var a, b bytes.Buffer
+	if err := WriteJSON(&a, sampleDb(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteJSON(&b, sampleDb(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != b.String() {
+		t.Error("two writes of the same db differ")
+	}
+}
+
+func TestWriteTableAlignsColumns(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, sampleDb()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want a header and 3 rows:\n%s", len(lines), buf.String())
+	}
+	// The value of every column starts where its header does, counting
+	// runes since specialties are in Cyrillic.
+	row := []string{"222", "201", "Физика", "250", "2", "1", "false"}
+	line := []rune(lines[3])
+	for i, name := range csvHeader {
+		offset := len([]rune(lines[0][:strings.Index(lines[0], name)]))
+		if !strings.HasPrefix(string(line[offset:]), row[i]+" ") && string(line[offset:]) != row[i] {
+			t.Errorf("column %s misaligned in %q", name, lines[3])
+		}
+	}
+}
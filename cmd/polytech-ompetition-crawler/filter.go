@@ -0,0 +1,112 @@
+package main
+
+// UserPredicate reports whether a user should be kept by FilterUsers.
+type UserPredicate func(User) bool
+
+// FilterUsers returns the users satisfying pred, keeping their order.
+func FilterUsers(users []User, pred UserPredicate) []User {
+	filtered := make([]User, 0, len(users))
+	for _, u := range users {
+		if pred(u) {
+			filtered = append(filtered, u)
+		}
+	}
+	return filtered
+}
+
+func HasOriginals() UserPredicate {
+	return func(u User) bool {
+		return u.HasOriginalDocuments
+	}
+}
+
+// HasAgreement keeps applicants who submitted their consent to enroll
+// (согласие на зачисление).
+func HasAgreement() UserPredicate {
+	return func(u User) bool {
+		return u.HasAgreement
+	}
+}
+
+// AgreedUsers returns the users with a consent to enroll. Only they take
+// seats when a direction is filled, so ranking AgreedUsers(users) with
+// RankWithinCapacity models the actual admission.
+func AgreedUsers(users []User) []User {
+	return FilterUsers(users, HasAgreement())
+}
+
+func StateIs(state string) UserPredicate {
+	return func(u User) bool {
+		return u.State == state
+	}
+}
+
+// ScoreAtLeast keeps users with a full score of at least threshold. Applicants
+// admitted without exams and those ranked on achievements alone have no
+// comparable score and are always kept.
+func ScoreAtLeast(threshold uint16) UserPredicate {
+	return func(u User) bool {
+		if u.WithoutExam || (len(u.Subjects) == 0 && u.HasAchievement) {
+			return true
+		}
+		return u.FullScore >= threshold
+	}
+}
+
+// WithMinScore drops users failing ScoreAtLeast(threshold) from every fetched
+// list, before the results are returned or stored.
+func WithMinScore(threshold uint16) Option {
+	return func(c *Crawler) {
+		c.minScore = threshold
+	}
+}
+
+// And is satisfied when all preds are; it is satisfied by no preds at all.
+func And(preds ...UserPredicate) UserPredicate {
+	return func(u User) bool {
+		for _, pred := range preds {
+			if !pred(u) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or is satisfied when any of preds is.
+func Or(preds ...UserPredicate) UserPredicate {
+	return func(u User) bool {
+		for _, pred := range preds {
+			if pred(u) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func Not(pred UserPredicate) UserPredicate {
+	return func(u User) bool {
+		return !pred(u)
+	}
+}
+
+// DedupeUsers drops repeated applicants, identified by UserUniqueId or by
+// UserSnils when the former is empty. The first occurrence is kept and the
+// relative order is preserved, so positions derived from the result are stable.
+func DedupeUsers(users []User) []User {
+	seen := make(map[string]struct{}, len(users))
+	deduped := make([]User, 0, len(users))
+	for _, u := range users {
+		key := "id:" + u.UserUniqueId
+		if u.UserUniqueId == "" {
+			key = "snils:" + u.UserSnils
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, u)
+	}
+	return deduped
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+var filterSample = []User{
+	{UserSnils: "1", State: "ACTIVE", HasOriginalDocuments: true, FullScore: 270},
+	{UserSnils: "2", State: "WITHDRAWN", HasOriginalDocuments: true, FullScore: 260},
+	{UserSnils: "3", State: "ACTIVE", FullScore: 250},
+	{UserSnils: "4", State: "ACTIVE", HasOriginalDocuments: true, FullScore: 240},
+}
+
+func snilsOf(users []User) []string {
+	out := make([]string, len(users))
+	for i, u := range users {
+		out[i] = u.UserSnils
+	}
+	return out
+}
+
+func TestFilterUsersCombined(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		pred UserPredicate
+		want []string
+	}{
+		{"originals", HasOriginals(), []string{"1", "2", "4"}},
+		{"active with originals", And(StateIs("ACTIVE"), HasOriginals()), []string{"1", "4"}},
+		{"withdrawn or no originals", Or(StateIs("WITHDRAWN"), Not(HasOriginals())), []string{"2", "3"}},
+		{"empty And", And(), []string{"1", "2", "3", "4"}},
+	} {
+		if got := snilsOf(FilterUsers(filterSample, tc.pred)); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDedupeUsers(t *testing.T) {
+	users := []User{
+		{UserUniqueId: "a", UserSnils: "1"},
+		{UserUniqueId: "b", UserSnils: "2"},
+		{UserUniqueId: "a", UserSnils: "1", FullScore: 100},
+		{UserSnils: "3"},
+		{UserSnils: "3"},
+		{UserUniqueId: "c", UserSnils: "3"},
+	}
+	got := DedupeUsers(users)
+	if want := []string{"1", "2", "3", "3"}; !slices.Equal(snilsOf(got), want) {
+		t.Errorf("users = %v, want %v", snilsOf(got), want)
+	}
+	if got[0].FullScore != 0 {
+		t.Error("first occurrence was not kept")
+	}
+}
+
+func TestWithMinScore(t *testing.T) {
+	srv := newRatingServer(t, `{"total": 4, "list": [
+		{"userSnils": "1", "fullScore": 270},
+		{"userSnils": "2", "fullScore": 150},
+		{"userSnils": "3", "withoutExam": true},
+		{"userSnils": "4", "hasAchievement": true}
+	]}`)
+	c := NewCrawler(srv.Client(), nil, WithMinScore(200))
+	users, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := snilsOf(users), []string{"1", "3", "4"}; !slices.Equal(got, want) {
+		t.Errorf("users = %v, want %v", got, want)
+	}
+}
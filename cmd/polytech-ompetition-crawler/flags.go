@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type config struct {
+	fromDirID uint64
+	toDirID   uint64
+	level     EducationLevel
+	form      EducationFormId
+	output    string
+	maskSnils bool
+	maskKey   string
+}
+
+var (
+	educationLevels = map[string]EducationLevel{
+		"bachelor": EducationLevelBachelor,
+		"master":   EducationLevelMaster,
+		"graduate": EducationLevelGraduate,
+	}
+	educationForms = map[string]EducationFormId{
+		"correspondence": EducationFormIdCorrespondence,
+		"fulltime":       EducationFormIdFullTime,
+		"parttime":       EducationFormIdPartTime,
+	}
+	// outputFormats write the crawled applicants together with the counters
+	// of their directions, which only the JSON output includes.
+	outputFormats = map[string]func(io.Writer, UserDb, map[uint64]DirectionStats) error{
+		"table": func(w io.Writer, db UserDb, _ map[uint64]DirectionStats) error {
+			return WriteTable(w, db)
+		},
+		"json": WriteJSON,
+		"csv": func(w io.Writer, db UserDb, _ map[uint64]DirectionStats) error {
+			return WriteCSV(w, db)
+		},
+	}
+)
+
+// parseFlags parses command line arguments (without the program name).
+// Usage is written to output on any parse or validation error.
+func parseFlags(args []string, output io.Writer) (config, error) {
+	fs := flag.NewFlagSet("polytech-competition-crawler", flag.ContinueOnError)
+	fs.SetOutput(output)
+	from := fs.Uint64("from", firstDirID, "first direction ID to crawl")
+	to := fs.Uint64("to", lastDirID, "last direction ID to crawl (inclusive)")
+	level := fs.String("level", string(EducationLevelMaster), "education level: BACHELOR, MASTER or GRADUATE")
+	form := fs.String("form", "fulltime", "education form: correspondence, fulltime or parttime")
+	format := fs.String("output", "table", "result format written to stdout: table, json or csv")
+	maskSnils := fs.Bool("mask-snils", false, "replace SNILS in the output with opaque tokens")
+	maskKey := fs.String("mask-key", "", "key for -mask-snils tokens, stable across runs (default $"+maskKeyEnv+", else random per run)")
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	cfg := config{
+		fromDirID: *from,
+		toDirID:   *to,
+		output:    strings.ToLower(*format),
+		maskSnils: *maskSnils,
+		maskKey:   *maskKey,
+	}
+	var ok bool
+	var err error
+	if cfg.level, ok = educationLevels[strings.ToLower(*level)]; !ok {
+		err = fmt.Errorf("invalid -level %q", *level)
+	} else if cfg.form, ok = educationForms[strings.ToLower(*form)]; !ok {
+		err = fmt.Errorf("invalid -form %q", *form)
+	} else if _, ok = outputFormats[cfg.output]; !ok {
+		err = fmt.Errorf("invalid -output %q", *format)
+	} else if cfg.fromDirID > cfg.toDirID {
+		err = fmt.Errorf("-from %d is greater than -to %d", cfg.fromDirID, cfg.toDirID)
+	}
+	if err != nil {
+		fmt.Fprintln(output, err)
+		fs.Usage()
+		return config{}, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseFlagsDefaults(t *testing.T) {
+	cfg, err := parseFlags(nil, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.fromDirID != firstDirID || cfg.toDirID != lastDirID || cfg.level != EducationLevelMaster {
+		t.Errorf("defaults = %+v", cfg)
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	cfg, err := parseFlags([]string{"-from", "210", "-to", "215", "-level", "bachelor", "-form", "parttime"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.fromDirID != 210 || cfg.toDirID != 215 || cfg.level != EducationLevelBachelor || cfg.form != EducationFormIdPartTime {
+		t.Errorf("cfg = %+v", cfg)
+	}
+}
+
+func TestParseFlagsRejectsInvalid(t *testing.T) {
+	for _, args := range [][]string{
+		{"-from", "300", "-to", "200"},
+		{"-level", "phd"},
+		{"-form", "remote"},
+		{"-from", "abc"},
+		{"-output", "xml"},
+	} {
+		var out strings.Builder
+		if _, err := parseFlags(args, &out); err == nil {
+			t.Errorf("%v: want an error", args)
+		}
+		if !strings.Contains(out.String(), "Usage") {
+			t.Errorf("%v: usage not printed, got %q", args, out.String())
+		}
+	}
+}
+
+func TestParseFlagsMaskKey(t *testing.T) {
+	cfg, err := parseFlags([]string{"-mask-snils", "-mask-key", "secret"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.maskSnils || cfg.maskKey != "secret" {
+		t.Errorf("cfg = %+v", cfg)
+	}
+}
+
+func TestOutputFormatsPassDirections(t *testing.T) {
+	directions := map[uint64]DirectionStats{200: {DirectionCapacity: 25, Total: 40, TotalWithOriginals: 10}}
+	var out strings.Builder
+	if err := outputFormats["json"](&out, sampleDb(), directions); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"directionCapacity": 25`) {
+		t.Errorf("json output lacks the direction counters:\n%s", out.String())
+	}
+	for _, format := range []string{"table", "csv"} {
+		if err := outputFormats[format](io.Discard, sampleDb(), directions); err != nil {
+			t.Errorf("%s: %v", format, err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// allForms lists the education forms in the order CrawlAllForms fetches them.
+var allForms = []EducationFormId{
+	EducationFormIdFullTime,
+	EducationFormIdPartTime,
+	EducationFormIdCorrespondence,
+}
+
+func CrawlAllForms(ctx context.Context, level EducationLevel, directionID uint64) (map[EducationFormId][]User, error) {
+	return defaultCrawler.CrawlAllForms(ctx, level, directionID)
+}
+
+// CrawlAllForms fetches the rating of directionID for every education form
+// of level, one request per form. A form without applicants maps to an
+// empty list. Forms that fail are missing from the map and their errors are
+// joined into the returned error.
+func (c *Crawler) CrawlAllForms(ctx context.Context, level EducationLevel, directionID uint64) (map[EducationFormId][]User, error) {
+	byForm := make(map[EducationFormId][]User, len(allForms))
+	var errs []error
+	for _, form := range allForms {
+		users, err := c.GetCompetitionList(ctx, BuildRatingURL(level, form, directionID))
+		switch {
+		case errors.Is(err, ErrNoUsers):
+			byForm[form] = []User{}
+		case err != nil:
+			errs = append(errs, fmt.Errorf("form %d: %w", form, err))
+		default:
+			byForm[form] = users
+		}
+	}
+	return byForm, errors.Join(errs...)
+}
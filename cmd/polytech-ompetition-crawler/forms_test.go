@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestCrawlAllForms(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("directioneducationformid") {
+		case strconv.Itoa(int(EducationFormIdFullTime)):
+			w.Write([]byte(samplePayload))
+		case strconv.Itoa(int(EducationFormIdPartTime)):
+			fmt.Fprint(w, `{"total": 1, "list": [{"userSnils": "333"}]}`)
+		default:
+			fmt.Fprint(w, `{"total": 0, "list": []}`)
+		}
+	}))
+	defer srv.Close()
+
+	byForm, err := NewCrawler(redirectedClient(t, srv), nil).CrawlAllForms(context.Background(), EducationLevelMaster, 250)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for form, want := range map[EducationFormId]int{
+		EducationFormIdFullTime:       2,
+		EducationFormIdPartTime:       1,
+		EducationFormIdCorrespondence: 0,
+	} {
+		users, ok := byForm[form]
+		if !ok || len(users) != want {
+			t.Errorf("form %d: %d users (present %t), want %d", form, len(users), ok, want)
+		}
+	}
+}
+
+func TestCrawlAllFormsPartialFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("directioneducationformid") == strconv.Itoa(int(EducationFormIdCorrespondence)) {
+			fmt.Fprint(w, `{"total": 4, "list": []}`)
+			return
+		}
+		if r.URL.Query().Get("directioneducationformid") == strconv.Itoa(int(EducationFormIdPartTime)) {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(samplePayload))
+	}))
+	defer srv.Close()
+
+	byForm, err := NewCrawler(redirectedClient(t, srv), nil).CrawlAllForms(context.Background(), EducationLevelMaster, 250)
+	if err == nil {
+		t.Error("want the error of the failed form")
+	}
+	if _, ok := byForm[EducationFormIdPartTime]; ok {
+		t.Error("failed form is in the map")
+	}
+	if users, ok := byForm[EducationFormIdCorrespondence]; !ok || len(users) != 0 {
+		t.Error("form without applicants is not an empty list")
+	}
+	if len(byForm[EducationFormIdFullTime]) != 2 {
+		t.Error("full-time users missing")
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"slices"
+	"time"
+)
+
+// PositionPoint is the position of an applicant in a direction at the time
+// of a snapshot.
+type PositionPoint struct {
+	At       time.Time
+	Position uint64
+}
+
+type Trend int
+
+const (
+	TrendStable  Trend = iota
+	TrendRising        // closer to the top of the list
+	TrendFalling       // further from the top of the list
+)
+
+func (t Trend) String() string {
+	switch t {
+	case TrendRising:
+		return "rising"
+	case TrendFalling:
+		return "falling"
+	default:
+		return "stable"
+	}
+}
+
+// PositionHistory tracks the positions of one applicant over a sequence of
+// snapshots, per direction.
+type PositionHistory struct {
+	Snils Snils
+	// series holds the points of every direction ordered by time.
+	series map[uint64][]PositionPoint
+}
+
+func NewPositionHistory(snils Snils) *PositionHistory {
+	return &PositionHistory{
+		Snils:  snils,
+		series: make(map[uint64][]PositionPoint),
+	}
+}
+
+// Add records the positions of the applicant in a snapshot taken at at.
+// Snapshots may be added in any order. A direction missing from one
+// snapshot, e.g. because its crawl failed, simply has no point for it.
+func (h *PositionHistory) Add(at time.Time, snapshot UserDb) {
+	for _, info := range snapshot[h.Snils] {
+		dir := info.u.DirectionId
+		point := PositionPoint{At: at, Position: info.position}
+		i, _ := slices.BinarySearchFunc(h.series[dir], at, func(p PositionPoint, t time.Time) int {
+			return p.At.Compare(t)
+		})
+		h.series[dir] = slices.Insert(h.series[dir], i, point)
+	}
+}
+
+// Directions returns the directions the applicant appeared in, sorted.
+func (h *PositionHistory) Directions() []uint64 {
+	dirs := make([]uint64, 0, len(h.series))
+	for dir := range h.series {
+		dirs = append(dirs, dir)
+	}
+	slices.Sort(dirs)
+	return dirs
+}
+
+// Series returns the positions in directionID ordered by time.
+func (h *PositionHistory) Series(directionID uint64) []PositionPoint {
+	return slices.Clone(h.series[directionID])
+}
+
+// Trend compares the first and the last known position in directionID.
+// Position 1 is the top of the list, so a smaller number is rising.
+func (h *PositionHistory) Trend(directionID uint64) Trend {
+	points := h.series[directionID]
+	if len(points) < 2 {
+		return TrendStable
+	}
+	first, last := points[0].Position, points[len(points)-1].Position
+	switch {
+	case last < first:
+		return TrendRising
+	case last > first:
+		return TrendFalling
+	default:
+		return TrendStable
+	}
+}
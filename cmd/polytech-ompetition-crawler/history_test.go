@@ -0,0 +1,50 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPositionHistory(t *testing.T) {
+	t0 := time.Date(2024, 7, 20, 10, 0, 0, 0, time.UTC)
+	snapshot := func(pos200, pos201 uint64) UserDb {
+		db := dbOf(UserInfo{position: pos200, u: &User{UserSnils: "1", DirectionId: 200}})
+		if pos201 > 0 {
+			db.addUserRow(UserInfo{position: pos201, u: &User{UserSnils: "1", DirectionId: 201}})
+		}
+		db.addUserRow(UserInfo{position: 1, u: &User{UserSnils: "2", DirectionId: 200}})
+		return db
+	}
+
+	h := NewPositionHistory("1")
+	// Added out of order; the 201 crawl failed in the second snapshot.
+	h.Add(t0.Add(2*time.Hour), snapshot(3, 5))
+	h.Add(t0, snapshot(7, 5))
+	h.Add(t0.Add(time.Hour), snapshot(4, 0))
+
+	if got := h.Directions(); !slices.Equal(got, []uint64{200, 201}) {
+		t.Errorf("directions = %v", got)
+	}
+	want := []PositionPoint{{t0, 7}, {t0.Add(time.Hour), 4}, {t0.Add(2 * time.Hour), 3}}
+	if got := h.Series(200); !reflect.DeepEqual(got, want) {
+		t.Errorf("series = %v, want %v", got, want)
+	}
+	if len(h.Series(201)) != 2 {
+		t.Errorf("series 201 = %v", h.Series(201))
+	}
+	if tr := h.Trend(200); tr != TrendRising {
+		t.Errorf("trend 200 = %v, want rising", tr)
+	}
+	if tr := h.Trend(201); tr != TrendStable {
+		t.Errorf("trend 201 = %v, want stable", tr)
+	}
+
+	falling := NewPositionHistory("1")
+	falling.Add(t0, snapshot(2, 0))
+	falling.Add(t0.Add(time.Hour), snapshot(9, 0))
+	if tr := falling.Trend(200); tr != TrendFalling {
+		t.Errorf("trend = %v, want falling", tr)
+	}
+}
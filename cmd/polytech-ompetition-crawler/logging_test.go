@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// captureHandler is a slog.Handler recording every record it handles.
+type captureHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *captureHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *captureHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r.Clone())
+	return nil
+}
+
+// find returns the attributes of the first record with the message msg.
+func (h *captureHandler) find(msg string) (map[string]slog.Value, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, r := range h.records {
+		if r.Message != msg {
+			continue
+		}
+		attrs := make(map[string]slog.Value)
+		r.Attrs(func(a slog.Attr) bool {
+			attrs[a.Key] = a.Value
+			return true
+		})
+		return attrs, true
+	}
+	return nil, false
+}
+
+func TestFailedRequestIsLogged(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	h := &captureHandler{}
+	c := NewCrawler(srv.Client(), nil, WithLogger(slog.New(h)))
+	if _, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=42"); err == nil {
+		t.Fatal("want an error for 503")
+	}
+	attrs, ok := h.find("request failed")
+	if !ok {
+		t.Fatal("no \"request failed\" record")
+	}
+	if d := attrs["direction"].String(); d != "42" {
+		t.Errorf("direction = %q, want 42", d)
+	}
+	if s := attrs["status"].Int64(); s != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", s)
+	}
+}
@@ -2,17 +2,17 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"go-competiotion-crawler/internal/worker_pool"
-	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"time"
 )
 
 const (
-	urlTemplate string = "https://enroll.spbstu.ru/applications-manager/api/v1/admission-list/form-rating?applicationEducationLevel=%s&directioneducationformid=%d&directionId=%d"
+	ratingURL string = "https://enroll.spbstu.ru/applications-manager/api/v1/admission-list/form-rating"
 )
 
 type (
@@ -87,52 +87,20 @@ type (
 	Response struct {
 		Users              []User `json:"list"`
 		Log                string `json:"-"`
-		DirectionCapacity  uint64 `json:"-"`
-		Total              uint64 `json:"-"`
-		TotalWithOriginals uint64 `json:"-"`
+		DirectionCapacity  uint64 `json:"directionCapacity"`
+		Total              uint64 `json:"total"`
+		TotalWithOriginals uint64 `json:"totalWithOriginals"`
+		DirectionTitle     string `json:"directionTitle"`
+		// LastUpdated is when the site last refreshed the list, zero if it
+		// is not given or in an unknown format.
+		LastUpdated Timestamp `json:"lastUpdated"`
 	}
 )
 
-var (
-	headers = map[string]string{
-		"User-Agent":      "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0",
-		"Accept":          "application/json",
-		"Accept-Language": "en-US,en;q=0.5",
-	}
-)
-
-func GetCompetitionList(ctx context.Context, url string) ([]User, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	for k, v := range headers {
-		req.Header.Set(k, v)
-	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	var resultResp Response
-	if err = json.Unmarshal(body, &resultResp); err != nil {
-		return nil, err
-	}
-	if len(resultResp.Users) < 1 {
-		return nil, fmt.Errorf("have not users for this directionId\n")
-	}
-	return resultResp.Users, nil
-}
-
 const (
 	firstDirID = 200
 	lastDirID  = 300
 	maxWorkers = 8
-	totalJobs  = lastDirID - firstDirID
 )
 
 type Result struct {
@@ -147,10 +115,40 @@ type UserInfo struct {
 }
 type UserDb map[Snils][]UserInfo
 
+// specialty returns the title shown for the direction, taken from the first subject.
+func (info UserInfo) specialty() string {
+	if len(info.u.Subjects) == 0 {
+		return ""
+	}
+	return info.u.Subjects[0].Title
+}
+
 func (db UserDb) addUserRow(userInfo UserInfo) {
 	db[Snils(userInfo.u.UserSnils)] = append(db[Snils(userInfo.u.UserSnils)], userInfo)
 }
-func (db UserDb) PrinUserRow(snils Snils) {
+
+// Merge adds the entries of other to db. An entry for a direction the
+// applicant already has in db is skipped, so db keeps its own position there.
+func (db UserDb) Merge(other UserDb) {
+	for snils, row := range other {
+		for _, info := range row {
+			if !db.hasDirection(snils, info.u.DirectionId) {
+				db[snils] = append(db[snils], info)
+			}
+		}
+	}
+}
+
+func (db UserDb) hasDirection(snils Snils, directionID uint64) bool {
+	for _, info := range db[snils] {
+		if info.u.DirectionId == directionID {
+			return true
+		}
+	}
+	return false
+}
+
+func (db UserDb) PrintUserRow(snils Snils) {
 	row, ok := db[Snils(snils)]
 	if !ok {
 		fmt.Printf("User not found\n")
@@ -159,38 +157,63 @@ func (db UserDb) PrinUserRow(snils Snils) {
 
 	fmt.Printf("User: %s\n", snils)
 	for _, info := range row {
-		fmt.Printf("Специальность: (%d) %s, сумма баллов: %d, приоритет: %d, позиция в списке: %d, оригинал: %t\n", info.u.DirectionId, info.u.Subjects[0].Title, info.u.FullScore, info.u.Priority, info.position, info.u.HasOriginalDocuments)
+		fmt.Printf("Специальность: (%d) %s, сумма баллов: %d, приоритет: %d, место в рейтинге: %d, оригинал: %t\n", info.u.DirectionId, info.specialty(), info.u.FullScore, info.u.Priority, info.position, info.u.HasOriginalDocuments)
 	}
 }
 func main() {
+	cfg, err := parseFlags(os.Args[1:], os.Stderr)
+	if err != nil {
+		os.Exit(2)
+	}
+	totalJobs := int(cfg.toDirID - cfg.fromDirID + 1)
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	crawler := NewCrawler(http.DefaultClient, nil, WithLogger(logger), WithClientPerWorker(maxWorkers))
+
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
-	pool := worker_pool.NewWorkerPoolWithCapacity[[]User](maxWorkers, totalJobs)
-	handles := make([]worker_pool.Handle[[]User], 0, totalJobs)
+	pool := worker_pool.NewWorkerPoolWithCapacity[*Response](maxWorkers, worker_pool.Capacity(totalJobs))
 	db := make(UserDb, totalJobs)
+	directions := make(map[uint64]DirectionStats, totalJobs)
 
-	for directionID := firstDirID; directionID <= lastDirID; directionID++ {
-		v, err := pool.Submit(func() ([]User, error) {
-			return GetCompetitionList(ctx, fmt.Sprintf(urlTemplate, EducationLevelMaster, EducationFormIdFullTime, directionID))
+	tasks := make([]func() (*Response, error), 0, totalJobs)
+	for directionID := cfg.fromDirID; directionID <= cfg.toDirID; directionID++ {
+		tasks = append(tasks, func() (*Response, error) {
+			return crawler.GetCompetitionListFull(ctx, BuildRatingURL(cfg.level, cfg.form, directionID))
 		})
-		if err != nil {
-			panic("submit error")
-		}
-		handles = append(handles, v)
 	}
+	progress := NewProgressReporter(os.Stderr, logger, len(tasks))
+	waitProgress := reportProgress(pool, progress, len(tasks))
+	handles, err := pool.SubmitBatch(tasks)
+	if err != nil {
+		panic("submit error")
+	}
+	pool.Done()
 
-	for _, h := range handles {
+	for i, h := range handles {
 		res, err := h.Get()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error occured while making request: %v\n", err)
+		var decodeErr *DecodeError
+		if errors.Is(err, ErrNoUsers) {
+			continue
+		} else if errors.As(err, &decodeErr) {
+			logger.Error("bad response data", "direction", directionID(decodeErr.URL), "error", decodeErr.Err)
+		} else if err != nil {
+			logger.Error("error occured while making request", "error", err)
 		} else {
-			for position, u := range res {
-				db.addUserRow(UserInfo{
-					position: uint64(position),
-					u:        &u,
-				})
+			directions[cfg.fromDirID+uint64(i)] = res.DirectionStats()
+			// Each entry points to its own User, not to a shared loop variable.
+			for _, info := range AssignPositions(res.Users) {
+				db.addUserRow(info)
 			}
 		}
 	}
+	waitProgress()
 
+	if cfg.maskSnils {
+		db = MaskedDb(db, snilsMaskKey(cfg.maskKey))
+	}
+	if err := outputFormats[cfg.output](os.Stdout, db, directions); err != nil {
+		logger.Error("failed to write results", "error", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestMergeSkipsKnownDirections(t *testing.T) {
+	master := dbOf(
+		UserInfo{position: 1, u: &User{UserSnils: "1", DirectionId: 200}},
+		UserInfo{position: 4, u: &User{UserSnils: "2", DirectionId: 200}},
+	)
+	bachelor := dbOf(
+		UserInfo{position: 9, u: &User{UserSnils: "2", DirectionId: 200}},
+		UserInfo{position: 2, u: &User{UserSnils: "2", DirectionId: 100}},
+		UserInfo{position: 3, u: &User{UserSnils: "3", DirectionId: 100}},
+	)
+	master.Merge(bachelor)
+
+	if len(master) != 3 {
+		t.Errorf("got %d applicants, want 3", len(master))
+	}
+	row := master["2"]
+	if len(row) != 2 {
+		t.Fatalf("applicant 2 has %d rows, want 2", len(row))
+	}
+	if row[0].u.DirectionId != 200 || row[0].position != 4 || row[1].u.DirectionId != 100 || row[1].position != 2 {
+		t.Errorf("applicant 2 rows = %+v, %+v", row[0], row[1])
+	}
+}
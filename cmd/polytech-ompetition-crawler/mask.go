@@ -0,0 +1,53 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+)
+
+// maskKeyEnv names the environment variable read when -mask-key is unset.
+const maskKeyEnv = "CRAWLER_MASK_KEY"
+
+// snilsMaskKey returns the key for MaskSnils: key if set, else the value of
+// CRAWLER_MASK_KEY, else a random one. There are only about a billion SNILS,
+// so a plain hash could be reversed by hashing all of them; the key
+// prevents that as long as it stays secret. A fixed key keeps tokens stable
+// between runs, so that outputs can be compared, but also lets anyone who
+// has seen a token together with its SNILS recognise it in later outputs.
+// A random key links nothing across runs, at the cost of having nothing to
+// compare.
+func snilsMaskKey(key string) []byte {
+	if key == "" {
+		key = os.Getenv(maskKeyEnv)
+	}
+	if key != "" {
+		return []byte(key)
+	}
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		panic("snils mask key: " + err.Error())
+	}
+	return random
+}
+
+// MaskSnils replaces s with an opaque token, the same for the same s and
+// key, from which s cannot be recovered without the key. The 12 hex digits
+// keep collisions unlikely far beyond the number of applicants.
+func MaskSnils(s Snils, key []byte) Snils {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(s))
+	return Snils(hex.EncodeToString(mac.Sum(nil))[:12])
+}
+
+// MaskedDb returns a copy of db keyed by MaskSnils with key, for output
+// that is shared with others.
+func MaskedDb(db UserDb, key []byte) UserDb {
+	masked := make(UserDb, len(db))
+	for snils, row := range db {
+		masked[MaskSnils(snils, key)] = row
+	}
+	return masked
+}
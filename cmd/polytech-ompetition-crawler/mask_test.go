@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestMaskSnilsStableForKey(t *testing.T) {
+	key := []byte("secret")
+	a := MaskSnils("111-111-111 11", key)
+	if b := MaskSnils("111-111-111 11", []byte("secret")); a != b {
+		t.Errorf("tokens for the same key differ: %q, %q", a, b)
+	}
+	if a == "111-111-111 11" || len(a) != 12 {
+		t.Errorf("token = %q, want 12 opaque hex digits", a)
+	}
+	if b := MaskSnils("111-111-111 11", []byte("other")); a == b {
+		t.Errorf("tokens for different keys are both %q", a)
+	}
+}
+
+func TestMaskSnilsNoCollisions(t *testing.T) {
+	key := []byte("secret")
+	seen := make(map[Snils]Snils)
+	for i := range 100000 {
+		s := Snils(fmt.Sprintf("%03d-%03d-%03d %02d", i/1000000, i/1000%1000, i%1000, i%100))
+		token := MaskSnils(s, key)
+		if prev, ok := seen[token]; ok {
+			t.Fatalf("%q and %q share token %q", prev, s, token)
+		}
+		seen[token] = s
+	}
+}
+
+func TestSnilsMaskKey(t *testing.T) {
+	t.Setenv(maskKeyEnv, "from-env")
+	if got := snilsMaskKey("from-flag"); string(got) != "from-flag" {
+		t.Errorf("flag key = %q, want from-flag", got)
+	}
+	if got := snilsMaskKey(""); string(got) != "from-env" {
+		t.Errorf("env key = %q, want from-env", got)
+	}
+
+	t.Setenv(maskKeyEnv, "")
+	a, b := snilsMaskKey(""), snilsMaskKey("")
+	if len(a) != 32 || bytes.Equal(a, b) {
+		t.Errorf("random keys = %x, %x; want two different 32-byte keys", a, b)
+	}
+}
+
+func TestMaskedDb(t *testing.T) {
+	db := dbOf(
+		UserInfo{position: 1, u: &User{UserSnils: "111-111-111 11", DirectionId: 200}},
+		UserInfo{position: 1, u: &User{UserSnils: "222-222-222 22", DirectionId: 200}},
+		UserInfo{position: 2, u: &User{UserSnils: "222-222-222 22", DirectionId: 201}},
+	)
+	key := []byte("secret")
+	masked := MaskedDb(db, key)
+	if len(masked) != len(db) {
+		t.Fatalf("masked %d applicants, want %d", len(masked), len(db))
+	}
+	for snils, row := range db {
+		if got := masked[MaskSnils(snils, key)]; len(got) != len(row) {
+			t.Errorf("%s: masked rows = %v, want %v", snils, got, row)
+		}
+	}
+}
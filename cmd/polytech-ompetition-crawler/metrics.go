@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strconv"
+	"time"
+)
+
+// Counter and Observer are the parts of metric types the Crawler uses.
+// prometheus.Counter and prometheus.Histogram satisfy them, so the crawler
+// can be instrumented without depending on a metrics library.
+type (
+	Counter interface {
+		Inc()
+	}
+	Observer interface {
+		Observe(float64)
+	}
+)
+
+// Metrics receives the measurements of every HTTP request made by a
+// Crawler. Nil fields are skipped. With Prometheus it could be set up as
+//
+//	failures := prometheus.NewCounterVec(opts, []string{"class"})
+//	m := Metrics{
+//		Requests: requestsTotal,
+//		Failures: func(class string) Counter { return failures.WithLabelValues(class) },
+//		Duration: durationHistogram,
+//	}
+type Metrics struct {
+	// Requests counts the requests sent.
+	Requests Counter
+	// Failures counts the failed requests by class: "4xx", "5xx", or
+	// "error" when no response was received.
+	Failures func(class string) Counter
+	// Duration observes the time until the response headers, in seconds.
+	Duration Observer
+}
+
+// WithMetrics reports the requests of the Crawler to m.
+func WithMetrics(m Metrics) Option {
+	return func(c *Crawler) {
+		c.metrics = &m
+	}
+}
+
+// observeRequest records a request that started at start and ended with
+// status, zero if it failed without a response.
+func (c *Crawler) observeRequest(start time.Time, status int) {
+	m := c.metrics
+	if m == nil {
+		return
+	}
+	if m.Requests != nil {
+		m.Requests.Inc()
+	}
+	if m.Duration != nil {
+		m.Duration.Observe(time.Since(start).Seconds())
+	}
+	if m.Failures == nil || (status >= 200 && status <= 299) {
+		return
+	}
+	class := "error"
+	if status > 0 {
+		class = strconv.Itoa(status/100) + "xx"
+	}
+	m.Failures(class).Inc()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type fakeCounter struct{ n atomic.Int64 }
+
+func (c *fakeCounter) Inc() { c.n.Add(1) }
+
+type fakeObserver struct {
+	mu     sync.Mutex
+	values []float64
+}
+
+func (o *fakeObserver) Observe(v float64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.values = append(o.values, v)
+}
+
+func TestMetricsCountRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("directionId") {
+		case "2":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "3":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Write([]byte(samplePayload))
+		}
+	}))
+	defer srv.Close()
+
+	var requests fakeCounter
+	var duration fakeObserver
+	var mu sync.Mutex
+	failures := make(map[string]*fakeCounter)
+	c := NewCrawler(srv.Client(), nil, WithMetrics(Metrics{
+		Requests: &requests,
+		Failures: func(class string) Counter {
+			mu.Lock()
+			defer mu.Unlock()
+			if failures[class] == nil {
+				failures[class] = &fakeCounter{}
+			}
+			return failures[class]
+		},
+		Duration: &duration,
+	}))
+
+	for _, id := range []string{"1", "2", "3", "1"} {
+		c.GetCompetitionList(context.Background(), srv.URL+"?directionId="+id)
+	}
+	// A closed server fails without a response.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	c.GetCompetitionList(context.Background(), closed.URL+"?directionId=1")
+
+	if n := requests.n.Load(); n != 5 {
+		t.Errorf("requests = %d, want 5", n)
+	}
+	if n := len(duration.values); n != 5 {
+		t.Errorf("observed %d durations, want 5", n)
+	}
+	for class, want := range map[string]int64{"5xx": 1, "4xx": 1, "error": 1} {
+		if got := failures[class]; got == nil || got.n.Load() != want {
+			t.Errorf("failures[%s] = %v, want %d", class, got, want)
+		}
+	}
+	if len(failures) != 3 {
+		t.Errorf("failure classes = %v, want 4xx, 5xx and error", failures)
+	}
+}
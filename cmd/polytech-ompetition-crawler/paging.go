@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// maxPages bounds the number of requests made for a single direction.
+const maxPages = 1000
+
+func GetCompetitionListPaged(ctx context.Context, rawURL string, size int) (*Response, error) {
+	return defaultCrawler.GetCompetitionListPaged(ctx, rawURL, size)
+}
+
+// GetCompetitionListPaged requests the list page by page (page starts at 0)
+// using the page and size query parameters. It stops on a page shorter than
+// size or once Total users are collected. A server ignoring the parameters
+// is detected by a repeated first row and the duplicate page is dropped.
+func (c *Crawler) GetCompetitionListPaged(ctx context.Context, rawURL string, size int) (*Response, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("invalid page size %d", size)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var full *Response
+	var prevFirst *User
+	for page := 0; page < maxPages; page++ {
+		q := u.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("size", strconv.Itoa(size))
+		u.RawQuery = q.Encode()
+
+		resp, err := c.fetch(ctx, u.String())
+		if err != nil {
+			return nil, err
+		}
+		if full == nil {
+			full = resp
+		} else {
+			if len(resp.Users) > 0 && prevFirst != nil && sameUser(*prevFirst, resp.Users[0]) {
+				break
+			}
+			full.Users = append(full.Users, resp.Users...)
+		}
+		if len(resp.Users) > 0 {
+			prevFirst = &resp.Users[0]
+		}
+		if len(resp.Users) < size || (full.Total > 0 && uint64(len(full.Users)) >= full.Total) {
+			break
+		}
+	}
+	return c.postProcess(ctx, rawURL, full)
+}
+
+func sameUser(a, b User) bool {
+	return a.UserUniqueId == b.UserUniqueId && a.UserSnils == b.UserSnils
+}
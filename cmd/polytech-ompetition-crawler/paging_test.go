@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+// pagedServer serves pages of size rows from users, honoring the page and
+// size query parameters unless ignorePaging is set.
+func pagedServer(t *testing.T, users []string, ignorePaging bool) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var page, size int
+		fmt.Sscan(r.URL.Query().Get("page"), &page)
+		fmt.Sscan(r.URL.Query().Get("size"), &size)
+		rows := users
+		if !ignorePaging {
+			start := min(page*size, len(users))
+			rows = users[start:min(start+size, len(users))]
+		}
+		w.Write([]byte(`{"list": [`))
+		for i, snils := range rows {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `{"userSnils": %q, "userUniqueId": %q}`, snils, snils)
+		}
+		w.Write([]byte(`]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestGetCompetitionListPaged(t *testing.T) {
+	users := []string{"1", "2", "3", "4"}
+	srv, requests := pagedServer(t, users, false)
+	c := NewCrawler(srv.Client(), nil)
+
+	resp, err := c.GetCompetitionListPaged(context.Background(), srv.URL+"?directionId=1", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two full pages, then an empty one ends the loop.
+	if got := snilsOf(resp.Users); !slices.Equal(got, users) {
+		t.Errorf("users = %v, want %v", got, users)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("made %d requests, want 3", n)
+	}
+}
+
+func TestGetCompetitionListPagedIgnoredByServer(t *testing.T) {
+	users := []string{"1", "2"}
+	srv, requests := pagedServer(t, users, true)
+	c := NewCrawler(srv.Client(), nil)
+
+	resp, err := c.GetCompetitionListPaged(context.Background(), srv.URL+"?directionId=1", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := snilsOf(resp.Users); !slices.Equal(got, users) {
+		t.Errorf("users = %v, want %v", got, users)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("made %d requests, want 2", n)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+func Ping(ctx context.Context) error {
+	return defaultCrawler.Ping(ctx)
+}
+
+// WithPingDirection sets the direction requested by Ping, firstDirID by
+// default. Any direction the site serves will do, as Ping only checks that
+// a rating comes back.
+func WithPingDirection(directionID uint64) Option {
+	return func(c *Crawler) {
+		c.pingDirection = directionID
+	}
+}
+
+// Ping checks that the rating API is reachable by requesting a single
+// direction, see WithPingDirection. It bypasses the cache and the circuit breaker and returns nil
+// if a rating JSON comes back with a 2xx status, even an empty one.
+func (c *Crawler) Ping(ctx context.Context) error {
+	url := c.directionURL(c.pingDirection)
+	if _, err := c.doFetch(ctx, url); err != nil {
+		return fmt.Errorf("ping %s: %w", url, err)
+	}
+	return nil
+}
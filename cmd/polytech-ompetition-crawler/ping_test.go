@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestPing(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		status  int
+		body    string
+		healthy bool
+	}{
+		{"rating", http.StatusOK, samplePayload, true},
+		{"empty rating", http.StatusOK, `{"list": []}`, true},
+		{"server error", http.StatusInternalServerError, "", false},
+		{"html page", http.StatusOK, "<html></html>", false},
+	} {
+		var gotDirection string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotDirection = r.URL.Query().Get("directionId")
+			w.WriteHeader(tc.status)
+			w.Write([]byte(tc.body))
+		}))
+		err := NewCrawler(redirectedClient(t, srv), nil).Ping(context.Background())
+		srv.Close()
+		if (err == nil) != tc.healthy {
+			t.Errorf("%s: Ping = %v", tc.name, err)
+		}
+		if gotDirection != strconv.Itoa(firstDirID) {
+			t.Errorf("%s: pinged direction %q", tc.name, gotDirection)
+		}
+	}
+}
+
+func TestPingDirection(t *testing.T) {
+	var gotDirection string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotDirection = r.URL.Query().Get("directionId")
+		w.Write([]byte(samplePayload))
+	}))
+	defer srv.Close()
+
+	if err := NewCrawler(redirectedClient(t, srv), nil, WithPingDirection(321)).Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if gotDirection != "321" {
+		t.Errorf("pinged direction %q, want 321", gotDirection)
+	}
+}
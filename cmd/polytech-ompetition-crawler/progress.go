@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go-competiotion-crawler/internal/worker_pool"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProgressReporter follows the directions of a crawl as they finish. It is
+// safe for concurrent use.
+type ProgressReporter interface {
+	// Advance records one finished direction, failed if err is not nil.
+	Advance(err error)
+	// Finish ends the report once all directions are done.
+	Finish()
+}
+
+const (
+	progressBarWidth    = 40
+	progressLogInterval = 2 * time.Second
+)
+
+// NewProgressReporter returns a progress bar drawn on w if it is a terminal,
+// and otherwise a reporter logging progress to logger every few seconds.
+func NewProgressReporter(w io.Writer, logger *slog.Logger, total int) ProgressReporter {
+	if isTerminal(w) {
+		return &barReporter{w: w, total: total}
+	}
+	return &logReporter{logger: logger, total: total}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// barReporter redraws a single line progress bar on every update.
+type barReporter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	total  int
+	done   int
+	failed int
+}
+
+func (b *barReporter) Advance(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done++
+	if err != nil {
+		b.failed++
+	}
+	filled := progressBarWidth
+	if b.total > 0 {
+		filled = min(b.done*progressBarWidth/b.total, progressBarWidth)
+	}
+	fmt.Fprintf(b.w, "\r[%s%s] %d/%d, %d failed",
+		strings.Repeat("#", filled), strings.Repeat(" ", progressBarWidth-filled), b.done, b.total, b.failed)
+}
+
+func (b *barReporter) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	fmt.Fprintln(b.w)
+}
+
+// logReporter logs the progress at most once per progressLogInterval and
+// when the last direction is done.
+type logReporter struct {
+	mu     sync.Mutex
+	logger *slog.Logger
+	total  int
+	done   int
+	failed int
+	last   time.Time
+}
+
+func (l *logReporter) Advance(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.done++
+	if err != nil {
+		l.failed++
+	}
+	if l.done < l.total && time.Since(l.last) < progressLogInterval {
+		return
+	}
+	l.last = time.Now()
+	l.logger.Info("progress", "done", l.done, "total", l.total, "failed", l.failed)
+}
+
+func (l *logReporter) Finish() {}
+
+// reportProgress advances progress as each of the total tasks of pool
+// finishes, whatever order their results are read in. Closed directions
+// (ErrNoUsers) are expected in a range of directions and are not counted as
+// failures. The returned function waits for all the tasks to be reported and
+// finishes progress.
+func reportProgress(pool worker_pool.WorkerPool[*Response], progress ProgressReporter, total int) func() {
+	var reported sync.WaitGroup
+	reported.Add(total)
+	pool.OnComplete(func(_ int, _ *Response, err error) {
+		defer reported.Done()
+		if errors.Is(err, ErrNoUsers) {
+			err = nil
+		}
+		progress.Advance(err)
+	})
+	return func() {
+		reported.Wait()
+		progress.Finish()
+	}
+}
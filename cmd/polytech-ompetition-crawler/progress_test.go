@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"go-competiotion-crawler/internal/worker_pool"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// safeBuilder is a strings.Builder safe for concurrent writes.
+type safeBuilder struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (s *safeBuilder) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *safeBuilder) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+// advanceAll calls p.Advance total times from several goroutines, failing
+// every tenth direction.
+func advanceAll(p ProgressReporter, total int) {
+	var wg sync.WaitGroup
+	for i := range total {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if i%10 == 0 {
+				err = errors.New("boom")
+			}
+			p.Advance(err)
+		}()
+	}
+	wg.Wait()
+	p.Finish()
+}
+
+func TestBarReporterReachesTotal(t *testing.T) {
+	var out safeBuilder
+	advanceAll(&barReporter{w: &out, total: 50}, 50)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\r")
+	last := lines[len(lines)-1]
+	want := "[" + strings.Repeat("#", progressBarWidth) + "] 50/50, 5 failed"
+	if last != want {
+		t.Errorf("last bar = %q, want %q", last, want)
+	}
+	if len(lines) != 51 {
+		t.Errorf("bar drawn %d times, want 50", len(lines)-1)
+	}
+}
+
+func TestLogReporterReachesTotal(t *testing.T) {
+	h := &captureHandler{}
+	advanceAll(&logReporter{logger: slog.New(h), total: 50}, 50)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.records) == 0 {
+		t.Fatal("no progress logged")
+	}
+	attrs := make(map[string]int64)
+	h.records[len(h.records)-1].Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.Int64()
+		return true
+	})
+	if attrs["done"] != 50 || attrs["total"] != 50 || attrs["failed"] != 5 {
+		t.Errorf("last progress = %v, want 50 of 50 done, 5 failed", attrs)
+	}
+}
+
+func TestNewProgressReporterWithoutTerminal(t *testing.T) {
+	if _, ok := NewProgressReporter(&strings.Builder{}, slog.Default(), 1).(*logReporter); !ok {
+		t.Error("want a log reporter for a writer that is not a terminal")
+	}
+}
+
+// countingReporter records the Advance calls it gets.
+type countingReporter struct {
+	mu       sync.Mutex
+	done     int
+	failed   int
+	finished bool
+}
+
+func (r *countingReporter) Advance(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done++
+	if err != nil {
+		r.failed++
+	}
+}
+
+func (r *countingReporter) Finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished = true
+}
+
+func (r *countingReporter) counts() (done, failed int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.done, r.failed
+}
+
+func TestReportProgressFollowsCompletion(t *testing.T) {
+	pool := worker_pool.NewWorkerPool[*Response](2)
+	progress := &countingReporter{}
+	wait := reportProgress(pool, progress, 3)
+
+	release := make(chan struct{})
+	handles, err := pool.SubmitBatch([]func() (*Response, error){
+		func() (*Response, error) { <-release; return &Response{}, nil },
+		func() (*Response, error) { return nil, ErrNoUsers },
+		func() (*Response, error) { return nil, errors.New("boom") },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Done()
+
+	// The later directions are reported while the first one still runs.
+	deadline := time.Now().Add(time.Second)
+	for done, _ := progress.counts(); done < 2; done, _ = progress.counts() {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d directions reported while the first one runs, want 2", done)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	handles[0].Get()
+	wait()
+
+	if done, failed := progress.counts(); done != 3 || failed != 1 || !progress.finished {
+		t.Errorf("reported %d done, %d failed, finished %t; want 3, 1 and finished", done, failed, progress.finished)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+	"strings"
+)
+
+// compareTop orders entries by full score, best first, falling back to the
+// enrollment order for equal scores.
+func compareTop(a, b UserInfo) int {
+	if c := cmp.Compare(b.u.FullScore, a.u.FullScore); c != 0 {
+		return c
+	}
+	return compareEnrollment(*a.u, *b.u)
+}
+
+// worstFirst is a heap keeping the weakest of the selected entries on top.
+type worstFirst []UserInfo
+
+func (h worstFirst) Len() int           { return len(h) }
+func (h worstFirst) Less(i, j int) bool { return compareTop(h[i], h[j]) > 0 }
+func (h worstFirst) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *worstFirst) Push(x any)        { *h = append(*h, x.(UserInfo)) }
+func (h *worstFirst) Pop() any {
+	old := *h
+	info := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return info
+}
+
+// TopN returns the n entries of db with the highest full score, best first.
+// It keeps only n entries in memory at a time instead of sorting all of them.
+func TopN(db UserDb, n int) []UserInfo {
+	if n <= 0 {
+		return nil
+	}
+	h := make(worstFirst, 0, n)
+	for _, row := range db {
+		for _, info := range row {
+			if h.Len() < n {
+				heap.Push(&h, info)
+			} else if compareTop(info, h[0]) < 0 {
+				h[0] = info
+				heap.Fix(&h, 0)
+			}
+		}
+	}
+	top := []UserInfo(h)
+	slices.SortFunc(top, compareTop)
+	return top
+}
+
+// FindByName returns the entries of db whose full name contains substr,
+// ignoring case, ordered by SNILS. The site masks parts of some names with
+// asterisks ("Иванов И***"); a masked part matches any text, so such a name
+// is returned whenever the unmasked name could contain substr.
+func FindByName(db UserDb, substr string) []UserInfo {
+	query := []rune(strings.ToLower(substr))
+	var found []UserInfo
+	for _, snils := range db.sortedSnils() {
+		for _, info := range db[snils] {
+			if maskedContains([]rune(strings.ToLower(info.u.UserFullName)), query) {
+				found = append(found, info)
+			}
+		}
+	}
+	return found
+}
+
+// maskedContains reports whether some text matched by name, where '*'
+// stands for any sequence of characters, contains query. It runs query
+// through the automaton of name starting from every position, since the
+// match may begin anywhere in the name.
+func maskedContains(name, query []rune) bool {
+	active := make([]bool, len(name)+1)
+	for i := range active {
+		active[i] = true
+	}
+	next := make([]bool, len(name)+1)
+	for _, r := range query {
+		clear(next)
+		moved := false
+		for i, ok := range active {
+			if !ok || i == len(name) {
+				continue
+			}
+			if name[i] == '*' {
+				next[i] = true
+				next[i+1] = true
+				moved = true
+			} else if name[i] == r {
+				next[i+1] = true
+				moved = true
+			}
+		}
+		if !moved {
+			return false
+		}
+		// A star may also match nothing.
+		for i := range next {
+			if next[i] && i < len(name) && name[i] == '*' {
+				next[i+1] = true
+			}
+		}
+		active, next = next, active
+	}
+	return true
+}
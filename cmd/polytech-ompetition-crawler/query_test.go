@@ -0,0 +1,83 @@
+package main
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestTopN(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	db := make(UserDb)
+	for i := range 200 {
+		db.addUserRow(UserInfo{u: &User{
+			UserSnils:   string(rune('a' + i%26)),
+			DirectionId: uint64(i),
+			FullScore:   uint16(rng.IntN(300)),
+		}})
+	}
+	top := TopN(db, 10)
+	if len(top) != 10 {
+		t.Fatalf("got %d entries, want 10", len(top))
+	}
+	for i := 1; i < len(top); i++ {
+		if top[i-1].u.FullScore < top[i].u.FullScore {
+			t.Errorf("entry %d (%d) below entry %d (%d)", i-1, top[i-1].u.FullScore, i, top[i].u.FullScore)
+		}
+	}
+	// Nothing outside the selection scores higher than its last entry.
+	selected := make(map[*User]bool)
+	for _, info := range top {
+		selected[info.u] = true
+	}
+	for _, row := range db {
+		for _, info := range row {
+			if !selected[info.u] && info.u.FullScore > top[9].u.FullScore {
+				t.Errorf("%d left out of the top", info.u.FullScore)
+			}
+		}
+	}
+	if TopN(db, 0) != nil {
+		t.Error("TopN(db, 0) is not empty")
+	}
+	if n := len(TopN(db, 500)); n != 200 {
+		t.Errorf("TopN beyond the size returned %d entries", n)
+	}
+}
+
+func TestFindByName(t *testing.T) {
+	db := dbOf(
+		UserInfo{u: &User{UserSnils: "1", UserFullName: "Иванов Иван Иванович"}},
+		UserInfo{u: &User{UserSnils: "3", UserFullName: "Сидорова Анна"}},
+		UserInfo{u: &User{UserSnils: "1", DirectionId: 1, UserFullName: "Иванов Иван Иванович"}},
+	)
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"ИВАНОВ", []string{"1", "1"}},
+		{"ова а", []string{"3"}},
+		{"петров", nil},
+	} {
+		if got := FindByName(db, tc.query); !slices.Equal(snilsOfInfos(got), tc.want) {
+			t.Errorf("%q: got %v, want %v", tc.query, snilsOfInfos(got), tc.want)
+		}
+	}
+}
+
+func TestFindByMaskedName(t *testing.T) {
+	db := dbOf(UserInfo{u: &User{UserSnils: "2", UserFullName: "Петров П*** Иванович"}})
+	for _, query := range []string{"петров павел иванович", "петров п", "ров пётр ив"} {
+		if got := FindByName(db, query); len(got) != 1 {
+			t.Errorf("%q does not match the masked name", query)
+		}
+	}
+}
+
+func snilsOfInfos(infos []UserInfo) []string {
+	var out []string
+	for _, info := range infos {
+		out = append(out, info.u.UserSnils)
+	}
+	return out
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+)
+
+// RankedUser is an applicant with its rank (starting from 1) in a direction
+// and whether that rank fits into the direction capacity.
+type RankedUser struct {
+	User
+	Rank     uint64
+	Admitted bool
+}
+
+// CompareUsers implements the order of the official competition list:
+// applicants admitted without entrance exams (без вступительных испытаний)
+// first, then higher total score, higher entrance subjects score, higher
+// achievement score, higher certificate average, and finally more preferred
+// (lower) priority. It returns a negative number when a goes before b.
+func CompareUsers(a, b User) int {
+	if a.WithoutExam != b.WithoutExam {
+		if a.WithoutExam {
+			return -1
+		}
+		return 1
+	}
+	if c := cmp.Compare(b.FullScore, a.FullScore); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.SubjectScore, a.SubjectScore); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.AchievementScore, a.AchievementScore); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.CertificateAverage, a.CertificateAverage); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Priority, b.Priority)
+}
+
+// SortUsers sorts users in place by CompareUsers, keeping the original
+// order of applicants the comparator considers equal.
+func SortUsers(users []User) {
+	slices.SortStableFunc(users, CompareUsers)
+}
+
+// compareEnrollment is CompareUsers with remaining ties broken by the
+// unique ID, so that rankings do not depend on the input order.
+func compareEnrollment(a, b User) int {
+	if c := CompareUsers(a, b); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.UserUniqueId, b.UserUniqueId)
+}
+
+// RankWithinCapacity sorts users in enrollment order and marks the first
+// capacity of them as admitted. The input slice is not modified.
+func RankWithinCapacity(users []User, capacity uint64) []RankedUser {
+	sorted := slices.Clone(users)
+	slices.SortStableFunc(sorted, compareEnrollment)
+
+	ranked := make([]RankedUser, len(sorted))
+	for i, u := range sorted {
+		rank := uint64(i) + 1
+		ranked[i] = RankedUser{
+			User:     u,
+			Rank:     rank,
+			Admitted: rank <= capacity,
+		}
+	}
+	return ranked
+}
+
+// PointsToCutoff returns for every applicant the full score of the last
+// admitted applicant, the capacity-th one in enrollment order, minus the
+// applicant's own score. Admitted applicants get zero or less; as ties are
+// broken on other criteria, so may an applicant just below the cutoff. When
+// the direction has no more applicants than seats, the last of them is the
+// cutoff. It returns nil for a zero capacity or no users.
+func PointsToCutoff(users []User, capacity uint64) map[Snils]int {
+	if capacity == 0 || len(users) == 0 {
+		return nil
+	}
+	ranked := RankWithinCapacity(users, capacity)
+	cutoff := ranked[min(capacity, uint64(len(ranked)))-1]
+	gaps := make(map[Snils]int, len(ranked))
+	for _, r := range ranked {
+		gaps[Snils(r.UserSnils)] = int(cutoff.FullScore) - int(r.FullScore)
+	}
+	return gaps
+}
+
+// AssignPositions ranks users of one direction by the enrollment order and
+// returns their entries in that order, the position being the rank starting
+// from 1. Unlike the index in the server list, it stays meaningful after the
+// list has been filtered, deduplicated or reordered.
+func AssignPositions(users []User) []UserInfo {
+	sorted := slices.Clone(users)
+	slices.SortStableFunc(sorted, compareEnrollment)
+	infos := make([]UserInfo, len(sorted))
+	for i := range sorted {
+		infos[i] = UserInfo{
+			position: uint64(i) + 1,
+			u:        &sorted[i],
+		}
+	}
+	return infos
+}
+
+// SimulateEnrollment assigns every applicant to at most one direction and
+// returns the direction ID per SNILS; applicants fitting nowhere are absent.
+//
+// Resolution follows deferred acceptance: each applicant applies to their
+// directions in priority order (lower Priority first). A direction holds
+// the best applicants up to its capacity by the enrollment order; when it
+// overflows, the lowest-ranked one is displaced and applies to their next
+// priority. This repeats until nobody is displaced, so a higher-ranked
+// applicant always wins a seat over a lower-ranked one. Directions missing
+// from capacities have no seats.
+func SimulateEnrollment(db UserDb, capacities map[uint64]uint64) map[Snils]uint64 {
+	prefs := make(map[Snils][]*User, len(db))
+	for snils, row := range db {
+		choices := make([]*User, 0, len(row))
+		for _, info := range row {
+			choices = append(choices, info.u)
+		}
+		slices.SortStableFunc(choices, func(a, b *User) int {
+			return cmp.Compare(a.Priority, b.Priority)
+		})
+		prefs[snils] = choices
+	}
+
+	queue := db.sortedSnils()
+	next := make(map[Snils]int, len(db))
+	held := make(map[uint64][]*User)
+	for len(queue) > 0 {
+		snils := queue[0]
+		queue = queue[1:]
+		if next[snils] >= len(prefs[snils]) {
+			continue
+		}
+		u := prefs[snils][next[snils]]
+		next[snils]++
+
+		dir := u.DirectionId
+		held[dir] = append(held[dir], u)
+		if uint64(len(held[dir])) <= capacities[dir] {
+			continue
+		}
+		slices.SortStableFunc(held[dir], func(a, b *User) int {
+			return compareEnrollment(*a, *b)
+		})
+		displaced := held[dir][len(held[dir])-1]
+		held[dir] = held[dir][:len(held[dir])-1]
+		queue = append(queue, Snils(displaced.UserSnils))
+	}
+
+	assignment := make(map[Snils]uint64, len(db))
+	for dir, users := range held {
+		for _, u := range users {
+			assignment[Snils(u.UserSnils)] = dir
+		}
+	}
+	return assignment
+}
+
+// SimulateAgreedEnrollment is SimulateEnrollment counting only the
+// applications with a consent to enroll, so that applicants without one
+// never take a seat however high their score.
+func SimulateAgreedEnrollment(db UserDb, capacities map[uint64]uint64) map[Snils]uint64 {
+	agreed := make(UserDb, len(db))
+	for snils, row := range db {
+		for _, info := range row {
+			if info.u.HasAgreement {
+				agreed[snils] = append(agreed[snils], info)
+			}
+		}
+	}
+	return SimulateEnrollment(agreed, capacities)
+}
+
+// Zone is a rough estimate of an applicant's chances, see AdmissionZone.
+type Zone int
+
+const (
+	ZoneRed Zone = iota
+	ZoneYellow
+	ZoneGreen
+)
+
+func (z Zone) String() string {
+	switch z {
+	case ZoneGreen:
+		return "green"
+	case ZoneYellow:
+		return "yellow"
+	default:
+		return "red"
+	}
+}
+
+// AdmissionZone estimates the chances of every applicant of one direction.
+// Only applicants who have handed in their original documents can take a
+// seat, so the heuristic counts them: with r being one plus the number of
+// original holders ranked above the applicant in enrollment order,
+//
+//   - green: r <= capacity, a seat is left even if all of them enroll here;
+//   - yellow: r <= 2*capacity, it depends on how many of them enroll into
+//     a direction of a higher priority;
+//   - red: otherwise.
+//
+// The applicant's own documents are not taken into account, as they can
+// still be handed in.
+func AdmissionZone(users []User, capacity uint64) map[Snils]Zone {
+	ranked := RankWithinCapacity(users, capacity)
+	zones := make(map[Snils]Zone, len(ranked))
+	var originalsAhead uint64
+	for _, r := range ranked {
+		switch rank := originalsAhead + 1; {
+		case rank <= capacity:
+			zones[Snils(r.UserSnils)] = ZoneGreen
+		case rank <= 2*capacity:
+			zones[Snils(r.UserSnils)] = ZoneYellow
+		default:
+			zones[Snils(r.UserSnils)] = ZoneRed
+		}
+		if r.HasOriginalDocuments {
+			originalsAhead++
+		}
+	}
+	return zones
+}
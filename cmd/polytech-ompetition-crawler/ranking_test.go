@@ -0,0 +1,195 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestRankWithinCapacity(t *testing.T) {
+	users := []User{
+		{UserSnils: "low", UserUniqueId: "e", FullScore: 200},
+		{UserSnils: "tie-b", UserUniqueId: "b", FullScore: 250, Priority: 1},
+		{UserSnils: "bvi", UserUniqueId: "d", WithoutExam: true},
+		{UserSnils: "tie-a", UserUniqueId: "a", FullScore: 250, Priority: 1},
+		{UserSnils: "high", UserUniqueId: "c", FullScore: 280},
+	}
+	ranked := RankWithinCapacity(users, 3)
+
+	want := []string{"bvi", "high", "tie-a", "tie-b", "low"}
+	var got []string
+	for i, r := range ranked {
+		got = append(got, r.UserSnils)
+		if r.Rank != uint64(i)+1 || r.Admitted != (i < 3) {
+			t.Errorf("%s: rank %d, admitted %t", r.UserSnils, r.Rank, r.Admitted)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if users[0].UserSnils != "low" {
+		t.Error("input slice was reordered")
+	}
+}
+
+func TestSimulateEnrollment(t *testing.T) {
+	db := make(UserDb)
+	for _, u := range []User{
+		{UserSnils: "A", UserUniqueId: "A", DirectionId: 1, Priority: 1, FullScore: 280},
+		{UserSnils: "B", UserUniqueId: "B", DirectionId: 1, Priority: 1, FullScore: 270},
+		{UserSnils: "B", UserUniqueId: "B", DirectionId: 2, Priority: 2, FullScore: 270},
+		{UserSnils: "C", UserUniqueId: "C", DirectionId: 2, Priority: 1, FullScore: 260},
+		{UserSnils: "C", UserUniqueId: "C", DirectionId: 3, Priority: 2, FullScore: 260},
+		{UserSnils: "D", UserUniqueId: "D", DirectionId: 2, Priority: 1, FullScore: 250},
+	} {
+		db.addUserRow(UserInfo{u: &u})
+	}
+	// B loses direction 1 to A and then displaces C from direction 2;
+	// direction 3 has no seats, so C and D stay out.
+	got := SimulateEnrollment(db, map[uint64]uint64{1: 1, 2: 1})
+	want := map[Snils]uint64{"A": 1, "B": 2}
+	if !maps.Equal(got, want) {
+		t.Errorf("assignment = %v, want %v", got, want)
+	}
+}
+
+func TestCompareUsersTieBreaks(t *testing.T) {
+	base := User{FullScore: 250, SubjectScore: 240, AchievementScore: 10, CertificateAverage: 4.5, Priority: 2}
+	for _, tc := range []struct {
+		level  string
+		modify func(*User)
+	}{
+		{"without exam", func(u *User) { u.WithoutExam = true }},
+		{"full score", func(u *User) { u.FullScore++ }},
+		{"subject score", func(u *User) { u.SubjectScore++ }},
+		{"achievements", func(u *User) { u.AchievementScore++ }},
+		{"certificate average", func(u *User) { u.CertificateAverage += 0.1 }},
+		{"priority", func(u *User) { u.Priority-- }},
+	} {
+		better := base
+		tc.modify(&better)
+		if c := CompareUsers(better, base); c >= 0 {
+			t.Errorf("%s: CompareUsers(better, worse) = %d, want < 0", tc.level, c)
+		}
+		if c := CompareUsers(base, better); c <= 0 {
+			t.Errorf("%s: CompareUsers(worse, better) = %d, want > 0", tc.level, c)
+		}
+	}
+	if c := CompareUsers(base, base); c != 0 {
+		t.Errorf("CompareUsers(u, u) = %d", c)
+	}
+}
+
+func TestSortUsersIsStable(t *testing.T) {
+	users := []User{
+		{UserSnils: "1", FullScore: 200},
+		{UserSnils: "2", FullScore: 250},
+		{UserSnils: "3", FullScore: 200},
+	}
+	SortUsers(users)
+	if got, want := snilsOf(users), []string{"2", "1", "3"}; !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestAssignPositionsUsesRank(t *testing.T) {
+	users := []User{
+		{UserSnils: "third", FullScore: 200},
+		{UserSnils: "first", FullScore: 280},
+		{UserSnils: "second", FullScore: 250},
+	}
+	want := map[string]uint64{"first": 1, "second": 2, "third": 3}
+	for _, info := range AssignPositions(users) {
+		if info.position != want[info.u.UserSnils] {
+			t.Errorf("%s at position %d, want %d", info.u.UserSnils, info.position, want[info.u.UserSnils])
+		}
+	}
+}
+
+func TestAssignPositionsDistinctUsers(t *testing.T) {
+	users := []User{
+		{UserSnils: "1", FullScore: 270},
+		{UserSnils: "2", FullScore: 260},
+		{UserSnils: "3", FullScore: 250},
+	}
+	db := make(UserDb)
+	for _, info := range AssignPositions(users) {
+		db.addUserRow(info)
+	}
+	seen := make(map[*User]bool)
+	for snils, row := range db {
+		u := row[0].u
+		if Snils(u.UserSnils) != snils || seen[u] {
+			t.Errorf("entry of %s points to %+v", snils, *u)
+		}
+		seen[u] = true
+	}
+	users[0].FullScore = 0
+	if db["1"][0].u.FullScore != 270 {
+		t.Error("stored entry aliases the input slice")
+	}
+}
+
+func TestPointsToCutoff(t *testing.T) {
+	users := []User{
+		{UserSnils: "a", FullScore: 280},
+		{UserSnils: "b", FullScore: 260},
+		{UserSnils: "c", FullScore: 250},
+		{UserSnils: "d", FullScore: 230},
+	}
+	got := PointsToCutoff(users, 2)
+	want := map[Snils]int{"a": -20, "b": 0, "c": 10, "d": 30}
+	if !maps.Equal(got, want) {
+		t.Errorf("gaps = %v, want %v", got, want)
+	}
+	// With more seats than applicants the last one is the cutoff.
+	if got := PointsToCutoff(users, 10); got["a"] != -50 || got["d"] != 0 {
+		t.Errorf("gaps with spare seats = %v", got)
+	}
+	if PointsToCutoff(users, 0) != nil || PointsToCutoff(nil, 3) != nil {
+		t.Error("want nil for no seats or no users")
+	}
+}
+
+func TestAgreedUsersTakeTheSeats(t *testing.T) {
+	users := []User{
+		{UserSnils: "top", UserUniqueId: "1", DirectionId: 1, FullScore: 300},
+		{UserSnils: "agreed-1", UserUniqueId: "2", DirectionId: 1, FullScore: 250, HasAgreement: true},
+		{UserSnils: "agreed-2", UserUniqueId: "3", DirectionId: 1, FullScore: 200, HasAgreement: true},
+	}
+	ranked := RankWithinCapacity(AgreedUsers(users), 1)
+	if len(ranked) != 2 || ranked[0].UserSnils != "agreed-1" || !ranked[0].Admitted || ranked[1].Admitted {
+		t.Errorf("ranked = %+v", ranked)
+	}
+
+	db := make(UserDb)
+	for i := range users {
+		db.addUserRow(UserInfo{u: &users[i]})
+	}
+	got := SimulateAgreedEnrollment(db, map[uint64]uint64{1: 1})
+	if want := map[Snils]uint64{"agreed-1": 1}; !maps.Equal(got, want) {
+		t.Errorf("assignment = %v, want %v", got, want)
+	}
+}
+
+func TestAdmissionZone(t *testing.T) {
+	users := []User{
+		{UserSnils: "a", UserUniqueId: "a", FullScore: 290, HasOriginalDocuments: true},
+		{UserSnils: "b", UserUniqueId: "b", FullScore: 280},
+		{UserSnils: "c", UserUniqueId: "c", FullScore: 270, HasOriginalDocuments: true},
+		{UserSnils: "d", UserUniqueId: "d", FullScore: 260, HasOriginalDocuments: true},
+		{UserSnils: "e", UserUniqueId: "e", FullScore: 250},
+		{UserSnils: "f", UserUniqueId: "f", FullScore: 240, HasOriginalDocuments: true},
+		{UserSnils: "g", UserUniqueId: "g", FullScore: 230},
+	}
+	// Originals ahead: a 0, b 1, c 1, d 2, e 3, f 3, g 4.
+	got := AdmissionZone(users, 2)
+	want := map[Snils]Zone{
+		"a": ZoneGreen, "b": ZoneGreen, "c": ZoneGreen,
+		"d": ZoneYellow, "e": ZoneYellow, "f": ZoneYellow,
+		"g": ZoneRed,
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("zones = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"go-competiotion-crawler/internal/worker_pool"
+	"testing"
+	"time"
+)
+
+func TestRateLimitSpacesRequests(t *testing.T) {
+	srv := newRatingServer(t, samplePayload)
+	c := NewCrawler(srv.Client(), nil, WithRateLimit(10))
+
+	const n = 6
+	pool := worker_pool.NewWorkerPool[[]User](n)
+	tasks := make([]func() ([]User, error), n)
+	for i := range tasks {
+		tasks[i] = func() ([]User, error) {
+			return c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1")
+		}
+	}
+	start := time.Now()
+	handles, err := pool.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Done()
+	if _, err := worker_pool.Collect(handles); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first request goes right away, each of the others 100ms later.
+	if elapsed, want := time.Since(start), (n-1)*100*time.Millisecond; elapsed < want {
+		t.Errorf("%d requests took %v, want at least %v", n, elapsed, want)
+	}
+}
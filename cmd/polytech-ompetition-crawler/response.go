@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// timestampLayouts are the formats the site has used for lastUpdated. The
+// ones without a zone are in Saint Petersburg time.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+}
+
+var mskZone = time.FixedZone("MSK", 3*60*60)
+
+// Timestamp is a time decoded leniently from any of the formats the site
+// has used, so that a change of the format never fails the whole list. An
+// unknown format decodes as the zero time.
+type Timestamp time.Time
+
+// Time returns t as a time.Time.
+func (t Timestamp) Time() time.Time {
+	return time.Time(t)
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		// Not a string, e.g. null: as lenient as an unknown format.
+		*t = Timestamp{}
+		return nil
+	}
+	*t = Timestamp(parseTimestamp(s))
+	return nil
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return time.Time(t).MarshalJSON()
+}
+
+func parseTimestamp(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, s, mskZone); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestGetCompetitionListFullMetadata(t *testing.T) {
+	srv := newRatingServer(t, `{
+		"directionTitle": "09.04.01 Информатика и вычислительная техника",
+		"lastUpdated": "2024-07-30T12:34:56.789+03:00",
+		"total": 1,
+		"list": [{"userSnils": "111-111-111 11", "fullScore": 270}]
+	}`)
+	c := NewCrawler(srv.Client(), nil)
+
+	resp, err := c.GetCompetitionListFull(context.Background(), srv.URL+"?directionId=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.DirectionTitle != "09.04.01 Информатика и вычислительная техника" {
+		t.Errorf("DirectionTitle = %q", resp.DirectionTitle)
+	}
+	want := time.Date(2024, 7, 30, 9, 34, 56, 789000000, time.UTC)
+	if !resp.LastUpdated.Time().Equal(want) {
+		t.Errorf("LastUpdated = %v, want %v", resp.LastUpdated.Time(), want)
+	}
+	if len(resp.Users) != 1 {
+		t.Errorf("users = %+v", resp.Users)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2024, 7, 30, 12, 34, 56, 0, mskZone)
+	for _, s := range []string{
+		"2024-07-30T12:34:56+03:00",
+		"2024-07-30T09:34:56Z",
+		"2024-07-30T12:34:56",
+		"2024-07-30 12:34:56",
+	} {
+		if got := parseTimestamp(s); !got.Equal(want) {
+			t.Errorf("parseTimestamp(%q) = %v, want %v", s, got, want)
+		}
+	}
+	for _, s := range []string{"", "yesterday", "30.07.2024"} {
+		if got := parseTimestamp(s); !got.IsZero() {
+			t.Errorf("parseTimestamp(%q) = %v, want zero", s, got)
+		}
+	}
+}
+
+func TestTimestampIsLenient(t *testing.T) {
+	for _, body := range []string{
+		`{"lastUpdated": null, "total": 3}`,
+		`{"lastUpdated": 1722332096, "total": 3}`,
+		`{"lastUpdated": "yesterday", "total": 3}`,
+	} {
+		var resp Response
+		if err := json.Unmarshal([]byte(body), &resp); err != nil {
+			t.Errorf("%s: %v", body, err)
+			continue
+		}
+		if !resp.LastUpdated.Time().IsZero() || resp.Total != 3 {
+			t.Errorf("%s: decoded %v, total %d", body, resp.LastUpdated.Time(), resp.Total)
+		}
+	}
+}
+
+// TestResponseLeavesDecodingToDecoder makes sure that only LastUpdated
+// customizes its decoding, so a Decoder set with WithDecoder does the rest.
+func TestResponseLeavesDecodingToDecoder(t *testing.T) {
+	if _, ok := any(&Response{}).(json.Unmarshaler); ok {
+		t.Error("Response implements json.Unmarshaler")
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// maxRetryAfterWait caps the total time spent waiting on 429 responses
+// for one request.
+const maxRetryAfterWait = time.Minute
+
+func GetCompetitionListWithRetry(ctx context.Context, url string, maxAttempts int, baseDelay time.Duration) ([]User, error) {
+	return defaultCrawler.GetCompetitionListWithRetry(ctx, url, maxAttempts, baseDelay)
+}
+
+// GetCompetitionListWithRetry makes up to maxAttempts requests, retrying
+// network errors and 5xx responses. The delay before the n-th retry is
+// baseDelay*2^(n-1) with ±50% jitter. 4xx responses and decode errors fail
+// immediately; the last error is returned once the attempts are exhausted,
+// or the retry budget if one is set with WithRetryBudget.
+func (c *Crawler) GetCompetitionListWithRetry(ctx context.Context, url string, maxAttempts int, baseDelay time.Duration) ([]User, error) {
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, backoff(baseDelay, attempt)); err != nil {
+				return nil, err
+			}
+		}
+		users, err := c.GetCompetitionList(ctx, url)
+		if err == nil {
+			return users, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil || !isRetryable(err) {
+			break
+		}
+		if attempt+1 < maxAttempts && !c.allowRetry() {
+			c.logger.WarnContext(ctx, "retry budget exhausted", "direction", directionID(url))
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+func isRetryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+func backoff(base time.Duration, attempt int) time.Duration {
+	d := base << (attempt - 1)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// fetchRateLimited repeats the request after the delay asked by a 429
+// response with a Retry-After header, until the delays would add up to more
+// than maxRetryAfterWait.
+func (c *Crawler) fetchRateLimited(ctx context.Context, url string) (*Response, error) {
+	var waited time.Duration
+	for {
+		resp, err := c.fetchGuarded(ctx, url)
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests ||
+			statusErr.RetryAfter <= 0 || waited+statusErr.RetryAfter > maxRetryAfterWait ||
+			!c.allowRetry() {
+			return resp, err
+		}
+		c.logger.InfoContext(ctx, "rate limited by server", "direction", directionID(url), "retry_after", statusErr.RetryAfter)
+		if err := sleepContext(ctx, statusErr.RetryAfter); err != nil {
+			return nil, err
+		}
+		waited += statusErr.RetryAfter
+	}
+}
+
+// parseRetryAfter accepts both forms of the header: delay seconds and
+// an HTTP date. It returns 0 when the header is missing or invalid.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer answers with status for the first failures requests and with
+// samplePayload afterwards. It returns the server and its request counter.
+func flakyServer(t *testing.T, status, failures int) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= int64(failures) {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(samplePayload))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestRetrySucceedsAfterServerErrors(t *testing.T) {
+	srv, calls := flakyServer(t, http.StatusInternalServerError, 2)
+	c := NewCrawler(srv.Client(), nil)
+
+	users, err := c.GetCompetitionListWithRetry(context.Background(), srv.URL+"?directionId=1", 5, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Errorf("got %d users, want 2", len(users))
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("made %d attempts, want 3", n)
+	}
+}
+
+func TestRetryGivesUpOnClientErrors(t *testing.T) {
+	srv, calls := flakyServer(t, http.StatusNotFound, 10)
+	c := NewCrawler(srv.Client(), nil)
+
+	if _, err := c.GetCompetitionListWithRetry(context.Background(), srv.URL+"?directionId=1", 5, time.Millisecond); err == nil {
+		t.Fatal("want an error")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("made %d attempts for a 404, want 1", n)
+	}
+}
+
+func TestRetryReturnsLastErrorWhenExhausted(t *testing.T) {
+	srv, calls := flakyServer(t, http.StatusServiceUnavailable, 10)
+	c := NewCrawler(srv.Client(), nil)
+
+	_, err := c.GetCompetitionListWithRetry(context.Background(), srv.URL+"?directionId=1", 3, time.Millisecond)
+	if statusErr, ok := err.(*StatusError); !ok || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("err = %v, want the 503 StatusError", err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("made %d attempts, want 3", n)
+	}
+}
+
+func TestRetryAfterOn429(t *testing.T) {
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(samplePayload))
+	}))
+	defer srv.Close()
+	c := NewCrawler(srv.Client(), nil)
+
+	start := time.Now()
+	users, err := c.GetCompetitionList(context.Background(), srv.URL+"?directionId=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least Retry-After", elapsed)
+	}
+	if len(users) != 2 || calls.Load() != 2 {
+		t.Errorf("got %d users in %d requests", len(users), calls.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := parseRetryAfter("3"); d != 3*time.Second {
+		t.Errorf("seconds form = %v", d)
+	}
+	date := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	if d := parseRetryAfter(date); d < 8*time.Second || d > 10*time.Second {
+		t.Errorf("date form = %v", d)
+	}
+	if d := parseRetryAfter("soon"); d != 0 {
+		t.Errorf("invalid value = %v", d)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// retryBudget is a token bucket of retries shared by all requests of a
+// Crawler, so that a struggling server does not get every failed request
+// repeated several times over.
+type retryBudget struct {
+	mu       sync.Mutex
+	tokens   float64
+	max      float64
+	interval time.Duration // time to earn one more retry, 0 for none
+	last     time.Time
+}
+
+// WithRetryBudget allows at most retries retries at once across all
+// requests, GetCompetitionListWithRetry attempts and waits on 429 responses
+// alike. If refill is positive, one more retry becomes available every
+// refill up to that maximum. Once the budget is spent, failures are
+// returned right away.
+func WithRetryBudget(retries int, refill time.Duration) Option {
+	return func(c *Crawler) {
+		c.retries = &retryBudget{
+			tokens:   float64(retries),
+			max:      float64(retries),
+			interval: refill,
+			last:     time.Now(),
+		}
+	}
+}
+
+// take spends a retry, reporting false if none is left.
+func (b *retryBudget) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if b.interval > 0 {
+		b.tokens = min(b.max, b.tokens+float64(now.Sub(b.last))/float64(b.interval))
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// allowRetry reports whether the Crawler may repeat a failed request.
+func (c *Crawler) allowRetry() bool {
+	return c.retries == nil || c.retries.take()
+}
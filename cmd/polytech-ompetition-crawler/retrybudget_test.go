@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRetryBudgetSharedAcrossDirections(t *testing.T) {
+	srv, calls := flakyServer(t, http.StatusServiceUnavailable, 100)
+	c := NewCrawler(srv.Client(), nil, WithRetryBudget(3, 0))
+
+	// The first direction spends the whole budget on its 3 retries.
+	if _, err := c.GetCompetitionListWithRetry(context.Background(), srv.URL+"?directionId=1", 5, time.Millisecond); err == nil {
+		t.Fatal("want an error")
+	}
+	if n := calls.Load(); n != 4 {
+		t.Errorf("first direction made %d attempts, want 4", n)
+	}
+
+	// The others fail after their first attempt.
+	for id := range 3 {
+		calls.Store(0)
+		url := srv.URL + "?directionId=" + strconv.Itoa(id+2)
+		if _, err := c.GetCompetitionListWithRetry(context.Background(), url, 5, time.Millisecond); err == nil {
+			t.Fatal("want an error")
+		}
+		if n := calls.Load(); n != 1 {
+			t.Errorf("direction %d made %d attempts after the budget ran out, want 1", id+2, n)
+		}
+	}
+}
+
+func TestRetryBudgetRefills(t *testing.T) {
+	b := &retryBudget{tokens: 1, max: 2, interval: time.Hour, last: time.Now()}
+	if !b.take() {
+		t.Fatal("first retry refused")
+	}
+	if b.take() {
+		t.Fatal("retry allowed with an empty budget")
+	}
+	// Two intervals later the budget is full again, but not above max.
+	b.last = b.last.Add(-2 * b.interval)
+	for i := range 2 {
+		if !b.take() {
+			t.Fatalf("retry %d refused after a refill", i+1)
+		}
+	}
+	if b.take() {
+		t.Error("budget refilled above its maximum")
+	}
+}
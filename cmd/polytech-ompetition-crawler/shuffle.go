@@ -0,0 +1,35 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+)
+
+// ShuffleDirections returns a copy of dirs in a random order determined by
+// seed, so that a crawl does not walk the ids sequentially yet can be
+// repeated exactly.
+func ShuffleDirections(dirs []uint64, seed int64) []uint64 {
+	shuffled := slices.Clone(dirs)
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
+
+// WithShuffle makes the Crawler submit directions in the order given by
+// ShuffleDirections with seed instead of the order they are passed in.
+func WithShuffle(seed int64) Option {
+	return func(c *Crawler) {
+		c.shuffle = true
+		c.shuffleSeed = seed
+	}
+}
+
+// order returns dirs in the submission order configured for c.
+func (c *Crawler) order(dirs []uint64) []uint64 {
+	if !c.shuffle {
+		return dirs
+	}
+	return ShuffleDirections(dirs, c.shuffleSeed)
+}
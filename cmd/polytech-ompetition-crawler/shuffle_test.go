@@ -0,0 +1,41 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestShuffleDirections(t *testing.T) {
+	dirs := make([]uint64, 100)
+	for i := range dirs {
+		dirs[i] = uint64(200 + i)
+	}
+	a, b := ShuffleDirections(dirs, 7), ShuffleDirections(dirs, 7)
+	if !slices.Equal(a, b) {
+		t.Error("the same seed gave different orders")
+	}
+	if slices.Equal(a, ShuffleDirections(dirs, 8)) {
+		t.Error("different seeds gave the same order")
+	}
+	if slices.Equal(a, dirs) {
+		t.Error("directions were not shuffled")
+	}
+	sorted := slices.Clone(a)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, dirs) {
+		t.Error("shuffle is not a permutation")
+	}
+	if dirs[0] != 200 {
+		t.Error("input was modified")
+	}
+}
+
+func TestWithShuffleOrder(t *testing.T) {
+	dirs := []uint64{1, 2, 3, 4, 5}
+	if got := NewCrawler(nil, nil).order(dirs); !slices.Equal(got, dirs) {
+		t.Errorf("default order = %v", got)
+	}
+	if got := NewCrawler(nil, nil, WithShuffle(3)).order(dirs); !slices.Equal(got, ShuffleDirections(dirs, 3)) {
+		t.Errorf("shuffled order = %v", got)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"database/sql"
+	"time"
+)
+
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS users (
+	snils                  TEXT    NOT NULL,
+	direction_id           INTEGER NOT NULL,
+	crawled_at             TEXT    NOT NULL,
+	position               INTEGER NOT NULL,
+	full_score             INTEGER NOT NULL,
+	priority               INTEGER NOT NULL,
+	has_original_documents INTEGER NOT NULL,
+	state                  TEXT    NOT NULL,
+	PRIMARY KEY (snils, direction_id, crawled_at)
+)`
+
+// sqliteInsert replaces a row with the same key, so that an applicant
+// listed twice in a direction is stored once, as its last entry.
+const sqliteInsert = `INSERT OR REPLACE INTO users
+	(snils, direction_id, crawled_at, position, full_score, priority, has_original_documents, state)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+// WriteSQLite stores snapshot in the users table, creating it if needed.
+// The caller opens db with a registered SQLite driver. ts is saved in UTC
+// as RFC 3339 text with nanoseconds, so snapshots taken within the same
+// second do not collide; all rows are inserted in a single transaction.
+func WriteSQLite(db *sql.DB, snapshot UserDb, ts time.Time) error {
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(sqliteInsert)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	crawledAt := ts.UTC().Format(time.RFC3339Nano)
+	for snils, row := range snapshot {
+		for _, info := range row {
+			_, err := stmt.Exec(
+				string(snils),
+				info.u.DirectionId,
+				crawledAt,
+				info.position,
+				info.u.FullScore,
+				info.u.Priority,
+				info.u.HasOriginalDocuments,
+				info.u.State,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to :memory: is a separate database.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestWriteSQLite(t *testing.T) {
+	db := openTestDB(t)
+	ts := time.Date(2024, 7, 20, 12, 0, 0, 0, time.UTC)
+	if err := WriteSQLite(db, sampleDb(), ts); err != nil {
+		t.Fatal(err)
+	}
+	// A second snapshot within the same second is stored next to the first.
+	if err := WriteSQLite(db, sampleDb(), ts.Add(time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 6 {
+		t.Errorf("stored %d rows, want 6", n)
+	}
+	var position, score int
+	var originals bool
+	err := db.QueryRow(`SELECT position, full_score, has_original_documents FROM users
+		WHERE snils = ? AND direction_id = ? AND crawled_at = ?`,
+		"111", 200, ts.Format(time.RFC3339Nano)).Scan(&position, &score, &originals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if position != 3 || score != 270 || !originals {
+		t.Errorf("row = %d, %d, %t; want 3, 270, true", position, score, originals)
+	}
+}
+
+func TestWriteSQLiteDuplicateApplicant(t *testing.T) {
+	db := openTestDB(t)
+	snapshot := dbOf(
+		UserInfo{position: 1, u: &User{UserSnils: "1", DirectionId: 200}},
+		UserInfo{position: 2, u: &User{UserSnils: "1", DirectionId: 200}},
+	)
+	if err := WriteSQLite(db, snapshot, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	var position int
+	if err := db.QueryRow(`SELECT position FROM users`).Scan(&position); err != nil {
+		t.Fatal(err)
+	}
+	if position != 2 {
+		t.Errorf("position = %d, want the last entry", position)
+	}
+}
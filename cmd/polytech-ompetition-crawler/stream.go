@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-competiotion-crawler/internal/worker_pool"
+	"os"
+)
+
+// syncEvery is the number of directions written between two fsyncs.
+const syncEvery = 10
+
+// jsonLine is one record written by StreamToFile.
+type jsonLine struct {
+	Snils Snils `json:"snils"`
+	jsonEntry
+}
+
+func StreamToFile(ctx context.Context, dirs []uint64, path string) error {
+	return defaultCrawler.StreamToFile(ctx, dirs, path)
+}
+
+// StreamToFile crawls dirs and appends every applicant entry to the file at
+// path as a JSON line, creating the file if needed. Directions are written
+// as they arrive, so only the directions being fetched or written are held
+// in memory, no more than the pool capacity. The file is synced to disk
+// every syncEvery directions and at the end.
+//
+// Failed directions do not stop the crawl; their errors are joined into the
+// returned error together with the first write error, if any.
+func (c *Crawler) StreamToFile(ctx context.Context, dirs []uint64, path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	bw := bufio.NewWriter(f)
+	enc := json.NewEncoder(bw)
+	flush := func() error {
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+		return f.Sync()
+	}
+
+	// Cancelled on a write error to stop submitting directions.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	pool := worker_pool.NewWorkerPoolWithContext[[]User](ctx, maxWorkers, maxWorkers)
+	defer pool.Done()
+
+	var errs []error
+	var writeErr error
+	written := 0
+	next := 0
+	dirs = c.order(dirs)
+	err = pool.SubmitAll(ctx, func() (func() ([]User, error), bool) {
+		if next == len(dirs) {
+			return nil, false
+		}
+		dir := dirs[next]
+		next++
+		return func() ([]User, error) {
+			users, err := c.GetCompetitionList(ctx, c.directionURL(dir))
+			if err != nil {
+				return nil, fmt.Errorf("direction %d: %w", dir, err)
+			}
+			return users, nil
+		}, true
+	}, func(users []User, err error) {
+		if err != nil {
+			if !errors.Is(err, ErrNoUsers) {
+				errs = append(errs, err)
+			}
+			return
+		}
+		if writeErr != nil {
+			return
+		}
+		for _, info := range AssignPositions(users) {
+			line := jsonLine{Snils: Snils(info.u.UserSnils), jsonEntry: newJSONEntry(info)}
+			if writeErr = enc.Encode(line); writeErr != nil {
+				cancel()
+				return
+			}
+		}
+		written++
+		if written%syncEvery == 0 {
+			if writeErr = flush(); writeErr != nil {
+				cancel()
+			}
+		}
+	})
+	if err != nil && writeErr == nil {
+		errs = append(errs, err)
+	}
+	if writeErr == nil {
+		writeErr = flush()
+	}
+	if writeErr != nil {
+		errs = append(errs, fmt.Errorf("write %s: %w", path, writeErr))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStreamToFile(t *testing.T) {
+	srv := newRatingServer(t, samplePayload)
+	c := NewCrawler(redirectedClient(t, srv), nil)
+	path := filepath.Join(t.TempDir(), "users.jsonl")
+
+	if err := c.StreamToFile(context.Background(), []uint64{200, 201, 202}, path); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records := 0
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var line jsonLine
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			t.Fatalf("line %d: %v", records+1, err)
+		}
+		if line.Snils == "" || line.Position == 0 {
+			t.Errorf("line %d = %+v", records+1, line)
+		}
+		records++
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if records != 6 {
+		t.Errorf("wrote %d records, want 6", records)
+	}
+}
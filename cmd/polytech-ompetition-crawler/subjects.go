@@ -0,0 +1,22 @@
+package main
+
+// SubjectScore returns the score of u in the subject with externalID and
+// whether u has a result in that subject at all.
+func SubjectScore(u User, externalID string) (uint16, bool) {
+	for _, s := range u.Subjects {
+		if s.ExternalId == externalID {
+			return s.Score, true
+		}
+	}
+	return 0, false
+}
+
+// SubjectMap returns the scores of u keyed by subject external ID. It is
+// empty, not nil, for an applicant without subjects.
+func SubjectMap(u User) map[string]uint16 {
+	scores := make(map[string]uint16, len(u.Subjects))
+	for _, s := range u.Subjects {
+		scores[s.ExternalId] = s.Score
+	}
+	return scores
+}
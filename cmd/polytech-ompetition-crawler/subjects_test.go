@@ -0,0 +1,38 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestSubjectScore(t *testing.T) {
+	u := User{Subjects: []Subject{
+		{Title: "Математика", ExternalId: "math", Score: 90},
+		{Title: "Физика", ExternalId: "phys", Score: 85},
+		{Title: "Русский язык", ExternalId: "rus", Score: 0},
+	}}
+
+	for id, want := range map[string]uint16{"math": 90, "phys": 85, "rus": 0} {
+		if got, ok := SubjectScore(u, id); !ok || got != want {
+			t.Errorf("SubjectScore(%q) = %d, %t; want %d, true", id, got, ok, want)
+		}
+	}
+	if got, ok := SubjectScore(u, "chem"); ok || got != 0 {
+		t.Errorf("SubjectScore(chem) = %d, %t; want 0, false", got, ok)
+	}
+
+	want := map[string]uint16{"math": 90, "phys": 85, "rus": 0}
+	if got := SubjectMap(u); !maps.Equal(got, want) {
+		t.Errorf("SubjectMap = %v, want %v", got, want)
+	}
+}
+
+func TestSubjectMapWithoutSubjects(t *testing.T) {
+	got := SubjectMap(User{})
+	if got == nil || len(got) != 0 {
+		t.Errorf("SubjectMap(User{}) = %#v, want an empty map", got)
+	}
+	if _, ok := SubjectScore(User{}, "math"); ok {
+		t.Error("SubjectScore found a subject of a user without subjects")
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"slices"
+	"sync"
+)
+
+// SyncUserDb is a UserDb safe for concurrent use, e.g. by workers adding
+// rows as their directions are crawled.
+type SyncUserDb struct {
+	mu sync.RWMutex
+	db UserDb
+}
+
+func NewSyncUserDb() *SyncUserDb {
+	return &SyncUserDb{db: make(UserDb)}
+}
+
+func (s *SyncUserDb) addUserRow(userInfo UserInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.db.addUserRow(userInfo)
+}
+
+func (s *SyncUserDb) PrintUserRow(snils Snils) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.db.PrintUserRow(snils)
+}
+
+// Snapshot returns a copy of the current contents which the caller owns.
+// The copied entries point to the same User values.
+func (s *SyncUserDb) Snapshot() UserDb {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snapshot := make(UserDb, len(s.db))
+	for snils, row := range s.db {
+		snapshot[snils] = slices.Clone(row)
+	}
+	return snapshot
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSyncUserDbConcurrentAdds(t *testing.T) {
+	db := NewSyncUserDb()
+	var wg sync.WaitGroup
+	for worker := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				db.addUserRow(UserInfo{position: uint64(i), u: &User{
+					UserSnils:   strconv.Itoa(i % 10),
+					DirectionId: uint64(worker),
+				}})
+				if i%10 == 0 {
+					db.Snapshot()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	snapshot := db.Snapshot()
+	if len(snapshot) != 10 {
+		t.Fatalf("got %d applicants, want 10", len(snapshot))
+	}
+	for snils, row := range snapshot {
+		if len(row) != 80 {
+			t.Errorf("applicant %s has %d rows, want 80", snils, len(row))
+		}
+	}
+	snapshot["0"] = nil
+	if len(db.Snapshot()["0"]) != 80 {
+		t.Error("modifying a snapshot changed the db")
+	}
+}
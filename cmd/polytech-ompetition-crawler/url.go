@@ -0,0 +1,16 @@
+package main
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// BuildRatingURL returns the form-rating endpoint URL for one direction
+// with all query parameters escaped.
+func BuildRatingURL(level EducationLevel, form EducationFormId, directionID uint64) string {
+	q := url.Values{}
+	q.Set("applicationEducationLevel", string(level))
+	q.Set("directioneducationformid", strconv.FormatUint(uint64(form), 10))
+	q.Set("directionId", strconv.FormatUint(directionID, 10))
+	return ratingURL + "?" + q.Encode()
+}
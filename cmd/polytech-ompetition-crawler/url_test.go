@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBuildRatingURL(t *testing.T) {
+	for _, level := range []EducationLevel{EducationLevelMaster, "A&B=C ?#"} {
+		raw := BuildRatingURL(level, EducationFormIdPartTime, 250)
+		if !strings.HasPrefix(raw, ratingURL+"?") {
+			t.Errorf("%q does not start with the endpoint", raw)
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := u.Query()
+		if got := q.Get("applicationEducationLevel"); got != string(level) {
+			t.Errorf("level = %q, want %q", got, level)
+		}
+		if got, want := q.Get("directioneducationformid"), strconv.Itoa(int(EducationFormIdPartTime)); got != want {
+			t.Errorf("form = %q, want %q", got, want)
+		}
+		if got := q.Get("directionId"); got != "250" {
+			t.Errorf("directionId = %q, want 250", got)
+		}
+		if len(q) != 3 {
+			t.Errorf("query has %d parameters, want 3", len(q))
+		}
+	}
+}
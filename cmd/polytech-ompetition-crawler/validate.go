@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"net/url"
+)
+
+// ValidateLevel splits users into those applying for the expected education
+// level and the rest.
+func ValidateLevel(users []User, expected EducationLevel) (valid, mismatched []User) {
+	for _, u := range users {
+		if EducationLevel(u.ApplicationEducationLevel) == expected {
+			valid = append(valid, u)
+		} else {
+			mismatched = append(mismatched, u)
+		}
+	}
+	return valid, mismatched
+}
+
+// WithLevelValidation drops users whose education level differs from the
+// applicationEducationLevel requested in the URL. Some endpoints are shared
+// between levels and occasionally return applicants of another one.
+func WithLevelValidation() Option {
+	return func(c *Crawler) {
+		c.validateLevel = true
+	}
+}
+
+func (c *Crawler) dropOtherLevels(ctx context.Context, rawURL string, users []User) []User {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return users
+	}
+	expected := EducationLevel(u.Query().Get("applicationEducationLevel"))
+	if expected == "" {
+		return users
+	}
+	valid, mismatched := ValidateLevel(users, expected)
+	if len(mismatched) > 0 {
+		c.logger.WarnContext(ctx, "dropped users of another education level",
+			"direction", directionID(rawURL), "level", expected, "dropped", len(mismatched))
+	}
+	return valid
+}
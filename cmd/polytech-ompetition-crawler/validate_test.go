@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+const mixedLevelPayload = `{"total": 3, "list": [
+	{"userSnils": "1", "applicationEducationLevel": "MASTER"},
+	{"userSnils": "2", "applicationEducationLevel": "BACHELOR"},
+	{"userSnils": "3", "applicationEducationLevel": "MASTER"}
+]}`
+
+func TestLevelValidationDropsOtherLevels(t *testing.T) {
+	srv := newRatingServer(t, mixedLevelPayload)
+	url := srv.URL + "?applicationEducationLevel=MASTER&directionId=1"
+
+	users, err := NewCrawler(srv.Client(), nil, WithLevelValidation()).GetCompetitionList(context.Background(), url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := snilsOf(users), []string{"1", "3"}; !slices.Equal(got, want) {
+		t.Errorf("users = %v, want %v", got, want)
+	}
+
+	// Without the option every user is kept.
+	users, err = NewCrawler(srv.Client(), nil).GetCompetitionList(context.Background(), url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 3 {
+		t.Errorf("got %d users without validation, want 3", len(users))
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go-competiotion-crawler/internal/worker_pool"
+	"time"
+)
+
+// WithEducation selects the education level and form of the directions
+// crawled by WatchDirections and friends. The default is full-time master.
+func WithEducation(level EducationLevel, form EducationFormId) Option {
+	return func(c *Crawler) {
+		c.level = level
+		c.form = form
+	}
+}
+
+func (c *Crawler) directionURL(directionID uint64) string {
+	return BuildRatingURL(c.level, c.form, directionID)
+}
+
+// crawl fetches every direction on pool and collects the applicants into a
+// new UserDb. It returns one error per failed direction and leaves the pool
+// shut down.
+func (c *Crawler) crawl(ctx context.Context, pool worker_pool.WorkerPool[[]User], dirs []uint64) (UserDb, []error) {
+	handles := make([]worker_pool.Handle[[]User], 0, len(dirs))
+	submitted := make([]uint64, 0, len(dirs))
+	var errs []error
+	for _, dir := range c.order(dirs) {
+		h, err := pool.Submit(func() ([]User, error) {
+			return c.GetCompetitionList(ctx, c.directionURL(dir))
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("direction %d: %w", dir, err))
+			continue
+		}
+		handles = append(handles, h)
+		submitted = append(submitted, dir)
+	}
+	pool.Done()
+
+	db := make(UserDb, len(dirs))
+	for i := range handles {
+		users, err := handles[i].Get()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("direction %d: %w", submitted[i], err))
+			continue
+		}
+		for _, info := range AssignPositions(users) {
+			db.addUserRow(info)
+		}
+	}
+	return db, errs
+}
+
+func WatchDirections(ctx context.Context, dirs []uint64, interval time.Duration, onSnapshot func(UserDb)) error {
+	return defaultCrawler.WatchDirections(ctx, dirs, interval, onSnapshot)
+}
+
+// WatchDirections crawls dirs every interval and passes each complete
+// snapshot to onSnapshot until ctx is done. Failed directions are logged and
+// missing from the snapshot. A crawl interrupted by ctx is not reported.
+// The same worker pool is reused for every iteration.
+func (c *Crawler) WatchDirections(ctx context.Context, dirs []uint64, interval time.Duration, onSnapshot func(UserDb)) error {
+	pool := worker_pool.NewWorkerPoolWithContext[[]User](ctx, maxWorkers, worker_pool.Capacity(len(dirs)))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		db, errs := c.crawl(ctx, pool, dirs)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		for _, err := range errs {
+			c.logger.WarnContext(ctx, "direction crawl failed", "error", err)
+		}
+		onSnapshot(db)
+
+		// All handles are resolved, so the workers are about to exit.
+		if err := pool.Shutdown(context.Background()); err != nil {
+			return err
+		}
+		if err := pool.Reset(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
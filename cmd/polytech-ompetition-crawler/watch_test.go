@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// redirectTransport sends every request to the test server instead of the
+// host in its URL, so URLs built with BuildRatingURL can be crawled.
+type redirectTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (rt *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return rt.next.RoundTrip(req)
+}
+
+// redirectedClient returns a client whose requests all go to srv.
+func redirectedClient(t *testing.T, srv *httptest.Server) *http.Client {
+	t.Helper()
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &http.Client{Transport: &redirectTransport{target: target, next: srv.Client().Transport}}
+}
+
+func TestWatchDirections(t *testing.T) {
+	srv := newRatingServer(t, samplePayload)
+	c := NewCrawler(redirectedClient(t, srv), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var snapshots []UserDb
+	err := c.WatchDirections(ctx, []uint64{200, 201, 202}, 10*time.Millisecond, func(db UserDb) {
+		snapshots = append(snapshots, db)
+		if len(snapshots) == 2 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("callback fired %d times, want 2", len(snapshots))
+	}
+	for i, db := range snapshots {
+		if len(db) != 2 || len(db["111-111-111 11"]) != 3 {
+			t.Errorf("snapshot %d = %v", i, db)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// NewRequestTask returns a worker pool task making a request bound to ctx
+// and returning the response body. Non-2xx responses fail with an error
+// carrying the status. A nil client falls back to http.DefaultClient.
+func NewRequestTask(ctx context.Context, client *http.Client, method, url string, headers map[string]string) func() (string, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func() (string, error) {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return "", err
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer func() {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return "", fmt.Errorf("unexpected status %d for %s %s", resp.StatusCode, method, url)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+		return string(body), nil
+	}
+}
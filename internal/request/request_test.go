@@ -0,0 +1,49 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewRequestTask(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Test") != "yes" || r.Method != http.MethodGet {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	task := NewRequestTask(context.Background(), srv.Client(), http.MethodGet, srv.URL, map[string]string{"X-Test": "yes"})
+	body, err := task()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "hello" {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestNewRequestTaskStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	_, err := NewRequestTask(context.Background(), srv.Client(), http.MethodGet, srv.URL, nil)()
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("err = %v, want an error with the status", err)
+	}
+}
+
+func TestNewRequestTaskContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewRequestTask(ctx, nil, http.MethodGet, "http://127.0.0.1:1", nil)(); err == nil {
+		t.Error("want an error for a cancelled context")
+	}
+}
@@ -0,0 +1,49 @@
+package worker_pool
+
+import (
+	"context"
+	"time"
+)
+
+// CtxWorkerPool is a pool of context-aware tasks. Each task gets a context
+// derived from the pool context, which is cancelled when the pool context
+// is or when the task handle is cancelled, so that tasks such as HTTP
+// requests can abort cleanly.
+type CtxWorkerPool[T any] interface {
+	Submit(func(context.Context) (T, error)) (Handle[T], error)
+	SubmitWithPriority(func(context.Context) (T, error), int) (Handle[T], error)
+	TrySubmit(func(context.Context) (T, error)) (Handle[T], bool)
+	Stats() Stats
+	OnComplete(func(index int, value T, err error))
+	Resize(Workers) error
+	SetIdleTimeout(time.Duration)
+	Shutdown(context.Context) error
+	Drain()
+	Done()
+}
+
+// ctxWorkerPool exposes the context-aware submission that workerPoolImpl
+// uses internally.
+type ctxWorkerPool[T any] struct {
+	*workerPoolImpl[T]
+}
+
+// NewCtxWorkerPool creates a CtxWorkerPool bound to ctx, see
+// NewWorkerPoolWithContext for the meaning of the arguments.
+func NewCtxWorkerPool[T any](ctx context.Context, workers Workers, capacity Capacity) CtxWorkerPool[T] {
+	return ctxWorkerPool[T]{NewWorkerPoolWithContext[T](ctx, workers, capacity).(*workerPoolImpl[T])}
+}
+
+// Submit queues proc like WorkerPool.Submit does.
+func (p ctxWorkerPool[T]) Submit(proc func(context.Context) (T, error)) (Handle[T], error) {
+	return p.enqueue(proc, 0, true)
+}
+
+func (p ctxWorkerPool[T]) SubmitWithPriority(proc func(context.Context) (T, error), priority int) (Handle[T], error) {
+	return p.enqueue(proc, priority, true)
+}
+
+func (p ctxWorkerPool[T]) TrySubmit(proc func(context.Context) (T, error)) (Handle[T], bool) {
+	h, err := p.enqueue(proc, 0, false)
+	return h, err == nil
+}
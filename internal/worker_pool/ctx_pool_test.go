@@ -0,0 +1,37 @@
+package worker_pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCtxWorkerPoolCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := NewCtxWorkerPool[int](ctx, 2, 8)
+	defer pool.Done()
+
+	started := make(chan struct{}, 2)
+	running := make([]Handle[int], 2)
+	for i := range running {
+		running[i], _ = pool.Submit(func(ctx context.Context) (int, error) {
+			started <- struct{}{}
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+	}
+	<-started
+	<-started
+	cancel()
+
+	wait := timeoutContext(t, time.Second)
+	for i := range running {
+		if _, err := running[i].GetWithContext(wait); !errors.Is(err, context.Canceled) {
+			t.Errorf("task %d: err = %v, want context.Canceled", i, err)
+		}
+	}
+	if _, err := pool.Submit(func(context.Context) (int, error) { return 0, nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Submit after cancel = %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package worker_pool
+
+// Drain is for callers giving up early, for example after WaitAny found
+// what they needed. Like Done it stops accepting tasks, and in addition it
+// discards every task still queued: their handles resolve with
+// ErrTaskCanceled and the tasks are never run. Running tasks finish as
+// usual. Results never have to be read for the workers to make progress,
+// so unread handles can simply be dropped. Drain does not wait for the
+// workers; call Shutdown for that.
+func (w *workerPoolImpl[T]) Drain() {
+	w.mu.Lock()
+	w.close()
+	w.mu.Unlock()
+
+	w.queue.each(func(t task[T]) {
+		if t.group == nil {
+			t.discard()
+		}
+	})
+	// The remaining tasks of groups, queued or already running.
+	w.groupsMu.Lock()
+	defer w.groupsMu.Unlock()
+	for _, g := range w.groups {
+		for _, t := range g.tasks {
+			t.discard()
+		}
+	}
+}
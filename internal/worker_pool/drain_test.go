@@ -0,0 +1,36 @@
+package worker_pool
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestDrainReleasesPool(t *testing.T) {
+	before := runtime.NumGoroutine()
+	pool := NewWorkerPoolWithCapacity[int](2, 100)
+	handles := make([]Handle[int], 100)
+	for i := range handles {
+		handles[i], _ = pool.Submit(func() (int, error) {
+			time.Sleep(time.Millisecond)
+			return i, nil
+		})
+	}
+	if v, err := handles[0].Get(); v != 0 || err != nil {
+		t.Fatalf("first task = %d, %v", v, err)
+	}
+	pool.Drain()
+	if err := pool.Shutdown(timeoutContext(t, time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handles[99].Get(); !errors.Is(err, ErrTaskCanceled) {
+		t.Errorf("last queued task: err = %v, want ErrTaskCanceled", err)
+	}
+	if _, err := pool.Submit(func() (int, error) { return 0, nil }); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit after Drain = %v", err)
+	}
+	waitFor(t, "pool goroutines to exit", func() bool {
+		return runtime.NumGoroutine() <= before
+	})
+}
@@ -0,0 +1,67 @@
+package worker_pool
+
+// group is a sequence of tasks run one after another by a single worker.
+// Its tasks are guarded by workerPoolImpl.groupsMu.
+type group[T any] struct {
+	key   string
+	tasks []task[T]
+}
+
+// SubmitGroup queues procs to run in order on a single worker, for example
+// all the pages of one rating so that they share a connection. Groups with
+// different keys run in parallel. While a group with the same key is still
+// queued or running, procs are appended to it instead, so tasks of one key
+// never overlap. A group takes one place in the queue however many tasks it
+// has. The handles are returned in the order of procs.
+func (w *workerPoolImpl[T]) SubmitGroup(key string, procs []func() (T, error)) ([]Handle[T], error) {
+	if len(procs) == 0 {
+		return nil, nil
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if err := w.accepting(); err != nil {
+		return nil, err
+	}
+	// The slot is taken up front since waiting for it while holding groupsMu
+	// would stall the workers draining groups.
+	if err := w.acquireSlot(true); err != nil {
+		return nil, err
+	}
+
+	w.groupsMu.Lock()
+	defer w.groupsMu.Unlock()
+	g, running := w.groups[key]
+	if !running {
+		g = &group[T]{key: key}
+		w.groups[key] = g
+	}
+	handles := make([]Handle[T], 0, len(procs))
+	for _, proc := range procs {
+		t, h := w.newTask(ignoreContext(proc), 0)
+		g.tasks = append(g.tasks, t)
+		handles = append(handles, h)
+	}
+	if running {
+		<-w.slots
+	} else {
+		// Queued behind the tasks submitted before its first one.
+		w.push(task[T]{seq: g.tasks[0].seq, group: g})
+	}
+	return handles, nil
+}
+
+// runGroup runs the tasks of g until there are none left and forgets g.
+func (w *workerPoolImpl[T]) runGroup(g *group[T]) {
+	for {
+		w.groupsMu.Lock()
+		if len(g.tasks) == 0 {
+			delete(w.groups, g.key)
+			w.groupsMu.Unlock()
+			return
+		}
+		t := g.tasks[0]
+		g.tasks = g.tasks[1:]
+		w.groupsMu.Unlock()
+		w.run(t)
+	}
+}
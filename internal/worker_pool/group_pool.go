@@ -0,0 +1,57 @@
+package worker_pool
+
+import (
+	"context"
+	"sync"
+)
+
+// GroupPool runs context-aware tasks like errgroup.Group, with typed
+// results: the first task to fail cancels the context of all the others,
+// still queued or running, and is what Wait returns.
+type GroupPool[T any] struct {
+	pool   *workerPoolImpl[T]
+	cancel context.CancelFunc
+
+	once sync.Once
+	err  error
+}
+
+// NewGroupPool creates a GroupPool with the default number of workers and
+// capacity, whose context is derived from ctx.
+func NewGroupPool[T any](ctx context.Context) *GroupPool[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	return &GroupPool[T]{
+		pool:   NewWorkerPoolWithContext[T](ctx, defaultWorkers, defaultCapacity).(*workerPoolImpl[T]),
+		cancel: cancel,
+	}
+}
+
+// Submit queues proc, blocking while the queue is full. Once a task has
+// failed, Submit returns an error wrapping context.Canceled.
+func (g *GroupPool[T]) Submit(proc func(context.Context) (T, error)) (Handle[T], error) {
+	return g.pool.enqueue(func(ctx context.Context) (T, error) {
+		// A panic must fail the group too, so recover it here rather than
+		// in the worker.
+		res := call(func() (T, error) { return proc(ctx) })
+		if res.e != nil {
+			g.fail(res.e)
+		}
+		return res.v, res.e
+	}, 0, true)
+}
+
+func (g *GroupPool[T]) fail(err error) {
+	g.once.Do(func() {
+		g.err = err
+		g.cancel()
+	})
+}
+
+// Wait stops accepting tasks, waits for all of them to finish and returns
+// the error of the first failed one, if any. Tasks dropped from the queue
+// because of that failure do not count as failures themselves.
+func (g *GroupPool[T]) Wait() error {
+	g.pool.Shutdown(context.Background())
+	g.cancel()
+	return g.err
+}
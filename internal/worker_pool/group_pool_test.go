@@ -0,0 +1,90 @@
+package worker_pool
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGroupPoolFirstErrorCancels(t *testing.T) {
+	g := NewGroupPool[int](context.Background())
+	boom := errors.New("boom")
+
+	// The failing task waits until the other one is submitted, which must
+	// then observe the cancellation whether it is running or still queued.
+	release := make(chan struct{})
+	if _, err := g.Submit(func(ctx context.Context) (int, error) {
+		<-release
+		return 0, boom
+	}); err != nil {
+		t.Fatal(err)
+	}
+	h, err := g.Submit(func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+
+	if err := g.Wait(); !errors.Is(err, boom) {
+		t.Errorf("Wait() = %v, want %v", err, boom)
+	}
+	if _, err := h.Get(); !errors.Is(err, context.Canceled) {
+		t.Errorf("other task = %v, want context.Canceled", err)
+	}
+}
+
+func TestGroupPoolPanicFailsGroup(t *testing.T) {
+	g := NewGroupPool[int](context.Background())
+
+	release := make(chan struct{})
+	h, err := g.Submit(func(ctx context.Context) (int, error) {
+		<-release
+		panic("oops")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := g.Submit(func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+
+	werr := g.Wait()
+	if werr == nil || !strings.Contains(werr.Error(), "task panicked: oops") {
+		t.Fatalf("Wait() = %v, want the panic", werr)
+	}
+	if _, err := h.Get(); err == nil || err.Error() != werr.Error() {
+		t.Errorf("panicked task = %v, want %v", err, werr)
+	}
+	if _, err := other.Get(); !errors.Is(err, context.Canceled) {
+		t.Errorf("other task = %v, want context.Canceled", err)
+	}
+}
+
+func TestGroupPoolSuccess(t *testing.T) {
+	g := NewGroupPool[int](context.Background())
+	var hs []Handle[int]
+	for i := range 5 {
+		h, err := g.Submit(func(ctx context.Context) (int, error) { return i * i, nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+		hs = append(hs, h)
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() = %v", err)
+	}
+	for i, h := range hs {
+		if v, err := h.Get(); err != nil || v != i*i {
+			t.Errorf("task %d = %d, %v; want %d, nil", i, v, err, i*i)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package worker_pool
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubmitGroupOrder(t *testing.T) {
+	pool := NewWorkerPool[int](2)
+	defer pool.Done()
+
+	var mu sync.Mutex
+	order := make(map[string][]int)
+	// The first task of each group waits for the other group to start,
+	// which only happens if the groups run in parallel.
+	started := map[string]chan struct{}{"a": make(chan struct{}), "b": make(chan struct{})}
+	other := map[string]string{"a": "b", "b": "a"}
+	procs := func(key string) []func() (int, error) {
+		var ps []func() (int, error)
+		for i := range 3 {
+			ps = append(ps, func() (int, error) {
+				mu.Lock()
+				order[key] = append(order[key], i)
+				mu.Unlock()
+				if i == 0 {
+					close(started[key])
+					<-started[other[key]]
+				}
+				time.Sleep(time.Millisecond)
+				return i, nil
+			})
+		}
+		return ps
+	}
+	ha, err := pool.SubmitGroup("a", procs("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hb, err := pool.SubmitGroup("b", procs("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := timeoutContext(t, time.Second)
+	for _, h := range append(ha, hb...) {
+		if _, err := h.GetWithContext(ctx); err != nil {
+			t.Fatalf("groups did not run in parallel: %v", err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for key, got := range order {
+		if !slices.Equal(got, []int{0, 1, 2}) {
+			t.Errorf("group %s ran in order %v", key, got)
+		}
+	}
+}
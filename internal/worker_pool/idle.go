@@ -0,0 +1,41 @@
+package worker_pool
+
+import "time"
+
+// SetIdleTimeout makes workers waiting longer than d for a task exit,
+// down to a single worker, so that a long-lived pool idle between batches
+// does not keep all its goroutines. Exited workers are spawned again, up
+// to the number set by the constructor or Resize, as tasks are submitted.
+// A zero d, the default, keeps every worker. The timeout applies to waits
+// starting after the call.
+func (w *workerPoolImpl[T]) SetIdleTimeout(d time.Duration) {
+	w.idleTimeout.Store(int64(max(d, 0)))
+}
+
+// retire accounts for the exit of a worker unless it is the last one,
+// reporting whether the worker may exit.
+func (w *workerPoolImpl[T]) retire() bool {
+	for {
+		live := w.live.Load()
+		if live <= 1 {
+			return false
+		}
+		if w.live.CompareAndSwap(live, live-1) {
+			return true
+		}
+	}
+}
+
+// respawn starts a worker for a newly queued task if some have exited on
+// idleness. It is called with w.mu held for reading, hence the pool is
+// open and at least one worker is alive to keep wg from reaching zero.
+func (w *workerPoolImpl[T]) respawn() {
+	if !w.idled.Load() {
+		return
+	}
+	w.resizeMu.Lock()
+	defer w.resizeMu.Unlock()
+	if w.live.Load() < int64(w.workers) {
+		w.spawn(1)
+	}
+}
@@ -0,0 +1,39 @@
+package worker_pool
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestIdleWorkersExitAndRespawn(t *testing.T) {
+	pool := NewWorkerPool[int](4)
+	defer pool.Done()
+	pool.SetIdleTimeout(20 * time.Millisecond)
+	// Every worker runs a task, so that its next wait has the timeout.
+	handles := make([]Handle[int], 8)
+	for i := range handles {
+		handles[i], _ = pool.Submit(func() (int, error) {
+			time.Sleep(time.Millisecond)
+			return i, nil
+		})
+	}
+	for i := range handles {
+		handles[i].Get()
+	}
+	busy := runtime.NumGoroutine()
+	waitFor(t, "idle workers to exit", func() bool { return liveWorkers(pool) == 1 })
+	if idle := runtime.NumGoroutine(); idle > busy-3 {
+		t.Errorf("%d goroutines after idleness, %d before", idle, busy)
+	}
+
+	release := make(chan struct{})
+	for range 4 {
+		pool.Submit(func() (int, error) {
+			<-release
+			return 0, nil
+		})
+	}
+	waitFor(t, "workers to respawn", func() bool { return liveWorkers(pool) == 4 })
+	close(release)
+}
@@ -0,0 +1,28 @@
+package worker_pool
+
+import "sync"
+
+// RunLimited runs tasks with at most n of them at a time and returns their
+// errors aligned with tasks. It is a lightweight alternative to a pool for
+// work without results: no queue, handles or result channels are involved.
+// Panics are reported as errors like in the pool.
+func RunLimited(n int, tasks []func() error) []error {
+	errs := make([]error, len(tasks))
+	sem := make(chan struct{}, max(n, 1))
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = call(func() (struct{}, error) {
+				return struct{}{}, task()
+			}).e
+		}()
+	}
+	wg.Wait()
+	return errs
+}
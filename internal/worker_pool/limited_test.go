@@ -0,0 +1,50 @@
+package worker_pool
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunLimitedConcurrency(t *testing.T) {
+	var running, peak atomic.Int64
+	errTask := errors.New("task")
+	tasks := make([]func() error, 100)
+	for i := range tasks {
+		tasks[i] = func() error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			time.Sleep(time.Millisecond)
+			switch i {
+			case 10:
+				return errTask
+			case 20:
+				panic("boom")
+			}
+			return nil
+		}
+	}
+	errs := RunLimited(4, tasks)
+	if p := peak.Load(); p > 4 {
+		t.Errorf("%d tasks ran at once, want at most 4", p)
+	}
+	for i, err := range errs {
+		switch i {
+		case 10:
+			if !errors.Is(err, errTask) {
+				t.Errorf("task 10: err = %v", err)
+			}
+		case 20:
+			if err == nil {
+				t.Error("task 20: panic not reported")
+			}
+		default:
+			if err != nil {
+				t.Errorf("task %d: err = %v", i, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package worker_pool
+
+// Map runs fn for every item on the pool and returns the results and errors
+// aligned with items: outs[i] and errs[i] belong to items[i]. Items that
+// could not be submitted get the Submit error.
+func Map[In, Out any](pool WorkerPool[Out], items []In, fn func(In) (Out, error)) ([]Out, []error) {
+	handles := make([]Handle[Out], len(items))
+	errs := make([]error, len(items))
+	submitted := make([]bool, len(items))
+	for i, item := range items {
+		h, err := pool.Submit(func() (Out, error) {
+			return fn(item)
+		})
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		handles[i] = h
+		submitted[i] = true
+	}
+
+	outs := make([]Out, len(items))
+	for i := range handles {
+		if submitted[i] {
+			outs[i], errs[i] = handles[i].Get()
+		}
+	}
+	return outs, errs
+}
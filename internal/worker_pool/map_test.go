@@ -0,0 +1,31 @@
+package worker_pool
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMapAlignsResultsWithItems(t *testing.T) {
+	pool := NewWorkerPool[int](4)
+	defer pool.Done()
+	errOdd := errors.New("seven")
+
+	items := []int{1, 2, 3, 7, 5, 6}
+	outs, errs := Map(pool, items, func(x int) (int, error) {
+		if x == 7 {
+			return 0, errOdd
+		}
+		return x * x, nil
+	})
+	for i, x := range items {
+		if x == 7 {
+			if !errors.Is(errs[i], errOdd) {
+				t.Errorf("item %d: err = %v, want errOdd", i, errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil || outs[i] != x*x {
+			t.Errorf("item %d = %d, %v; want %d, nil", i, outs[i], errs[i], x*x)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package worker_pool
+
+import "sync"
+
+// completion is either a finished task or, when after is set, an action to
+// run once all the completions queued before it are delivered.
+type completion[T any] struct {
+	index int
+	res   result[T]
+	after func()
+}
+
+// notifier runs completion callbacks outside of the workers. Completions
+// are queued without bound and delivered in order by a single goroutine
+// which only lives while there is something to deliver.
+type notifier[T any] struct {
+	mu      sync.Mutex
+	fns     []func(int, T, error)
+	queue   []completion[T]
+	running bool
+}
+
+func (n *notifier[T]) register(fn func(int, T, error)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.fns = append(n.fns, fn)
+}
+
+func (n *notifier[T]) emit(index int, res result[T]) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.fns) == 0 {
+		return
+	}
+	n.push(completion[T]{index: index, res: res})
+}
+
+// afterPending runs fn on the notifier goroutine once everything emitted so
+// far has been delivered.
+func (n *notifier[T]) afterPending(fn func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.push(completion[T]{after: fn})
+}
+
+// push must be called with mu held.
+func (n *notifier[T]) push(c completion[T]) {
+	n.queue = append(n.queue, c)
+	if !n.running {
+		n.running = true
+		go n.run()
+	}
+}
+
+func (n *notifier[T]) run() {
+	for {
+		n.mu.Lock()
+		if len(n.queue) == 0 {
+			n.running = false
+			n.mu.Unlock()
+			return
+		}
+		batch, fns := n.queue, n.fns
+		n.queue = nil
+		n.mu.Unlock()
+
+		for _, c := range batch {
+			if c.after != nil {
+				c.after()
+				continue
+			}
+			for _, fn := range fns {
+				fn(c.index, c.res.v, c.res.e)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package worker_pool
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestOnCompleteReportsEveryTask(t *testing.T) {
+	pool := NewWorkerPool[int](3)
+	defer pool.Done()
+	errOdd := errors.New("odd")
+	release := make(chan struct{})
+	var mu sync.Mutex
+	seen := make(map[int]error)
+	pool.OnComplete(func(index int, value int, err error) {
+		<-release
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil && value != index*10 {
+			t.Errorf("task %d reported value %d", index, value)
+		}
+		seen[index] = err
+	})
+
+	var handles []Handle[int]
+	for i := range 10 {
+		h, _ := pool.Submit(func() (int, error) {
+			if i%2 == 1 {
+				return 0, errOdd
+			}
+			return i * 10, nil
+		})
+		handles = append(handles, h)
+	}
+	// The callback is blocked, yet the workers finish every task.
+	for i := range handles {
+		handles[i].Get()
+	}
+	close(release)
+
+	waitFor(t, "all completions", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(seen) == 10
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	for i, err := range seen {
+		if (i%2 == 1) != errors.Is(err, errOdd) {
+			t.Errorf("task %d reported err %v", i, err)
+		}
+	}
+}
+
+func TestStreamYieldsEveryResult(t *testing.T) {
+	pool := NewWorkerPool[int](3)
+	stream := pool.Stream()
+	for i := range 20 {
+		pool.Submit(func() (int, error) { return i, nil })
+	}
+	pool.Done()
+
+	seen := make(map[int]bool)
+	for h := range stream {
+		v, err := h.Get()
+		if err != nil || seen[v] {
+			t.Errorf("streamed %d, %v", v, err)
+		}
+		seen[v] = true
+	}
+	// The loop ends only once the stream is closed.
+	if len(seen) != 20 {
+		t.Errorf("streamed %d results, want 20", len(seen))
+	}
+}
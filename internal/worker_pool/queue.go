@@ -0,0 +1,59 @@
+package worker_pool
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// taskQueue holds pending tasks ordered by priority, higher first, and by
+// submission order among equal priorities.
+type taskQueue[T any] struct {
+	mu    sync.Mutex
+	tasks taskHeap[T]
+}
+
+func (q *taskQueue[T]) push(t task[T]) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	heap.Push(&q.tasks, t)
+}
+
+// pop must only be called when the queue is known to be non-empty.
+func (q *taskQueue[T]) pop() task[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return heap.Pop(&q.tasks).(task[T])
+}
+
+// each calls fn for every queued task, in no particular order. fn must not
+// use the queue.
+func (q *taskQueue[T]) each(fn func(task[T])) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, t := range q.tasks {
+		fn(t)
+	}
+}
+
+type taskHeap[T any] []task[T]
+
+func (h taskHeap[T]) Len() int { return len(h) }
+
+func (h taskHeap[T]) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h taskHeap[T]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *taskHeap[T]) Push(x any) { *h = append(*h, x.(task[T])) }
+
+func (h *taskHeap[T]) Pop() any {
+	old := *h
+	t := old[len(old)-1]
+	old[len(old)-1] = task[T]{}
+	*h = old[:len(old)-1]
+	return t
+}
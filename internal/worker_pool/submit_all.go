@@ -0,0 +1,63 @@
+package worker_pool
+
+import (
+	"context"
+	"sync"
+)
+
+// SubmitAll submits the tasks produced by gen until it reports false and
+// passes every result to collect as it arrives. No more than the pool
+// capacity of tasks are outstanding at a time, whether queued, running or
+// waiting to be collected, so gen may produce any number of tasks. collect
+// is called from a single goroutine.
+//
+// SubmitAll returns after all submitted tasks are collected. The error is
+// ctx.Err() if ctx ended the generation early, or the Submit error if the
+// pool stopped accepting tasks.
+func (w *workerPoolImpl[T]) SubmitAll(ctx context.Context, gen func() (func() (T, error), bool), collect func(T, error)) error {
+	limit := cap(w.slots)
+	sem := make(chan struct{}, limit)
+	results := make(chan result[T], limit)
+	var pending sync.WaitGroup
+
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for r := range results {
+			collect(r.v, r.e)
+			<-sem
+		}
+	}()
+
+	var err error
+	for err == nil {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			err = ctx.Err()
+			continue
+		}
+		proc, ok := gen()
+		if !ok {
+			<-sem
+			break
+		}
+		h, submitErr := w.Submit(proc)
+		if submitErr != nil {
+			<-sem
+			err = submitErr
+			break
+		}
+		pending.Add(1)
+		go func() {
+			defer pending.Done()
+			v, err := h.Get()
+			results <- result[T]{e: err, v: v}
+		}()
+	}
+
+	pending.Wait()
+	close(results)
+	<-collected
+	return err
+}
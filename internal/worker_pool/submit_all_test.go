@@ -0,0 +1,66 @@
+package worker_pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSubmitAllBoundsOutstandingTasks(t *testing.T) {
+	pool := NewWorkerPoolWithCapacity[int](4, 16)
+	defer pool.Done()
+
+	const total = 10_000
+	generated, peak, sum := 0, 0, 0
+	// outstanding is shared by gen and collect, which run on different
+	// goroutines.
+	outstanding := make(chan int, 1)
+	outstanding <- 0
+	gen := func() (func() (int, error), bool) {
+		if generated == total {
+			return nil, false
+		}
+		generated++
+		n := <-outstanding + 1
+		peak = max(peak, n)
+		outstanding <- n
+		return func() (int, error) { return 1, nil }, true
+	}
+	collect := func(v int, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		sum += v
+		outstanding <- <-outstanding - 1
+	}
+	if err := pool.SubmitAll(context.Background(), gen, collect); err != nil {
+		t.Fatal(err)
+	}
+	if sum != total {
+		t.Errorf("collected %d results, want %d", sum, total)
+	}
+	if peak > 16 {
+		t.Errorf("%d tasks outstanding at peak, want at most 16", peak)
+	}
+}
+
+func TestSubmitAllStopsOnContext(t *testing.T) {
+	pool := NewWorkerPoolWithCapacity[int](2, 4)
+	defer pool.Done()
+	ctx, cancel := context.WithCancel(context.Background())
+	n := 0
+	gen := func() (func() (int, error), bool) {
+		if n++; n == 10 {
+			cancel()
+		}
+		return func() (int, error) { return 0, nil }, true
+	}
+	collected := 0
+	err := pool.SubmitAll(ctx, gen, func(int, error) { collected++ })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if collected != n {
+		t.Errorf("collected %d of %d submitted tasks", collected, n)
+	}
+}
@@ -0,0 +1,124 @@
+package worker_pool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+var ErrNoHandles = errors.New("worker pool: no handles to wait on")
+
+// WaitAny blocks until one of the handles is resolved and returns its index
+// and result. Handles that already hold a result win immediately. The result
+// is cached in handles[i], so a later Get on it returns the same value.
+func WaitAny[T any](handles []Handle[T]) (int, T, error) {
+	if len(handles) == 0 {
+		var zero T
+		return -1, zero, ErrNoHandles
+	}
+	for i := range handles {
+		if handles[i].invoked {
+			v, err := handles[i].Get()
+			return i, v, err
+		}
+	}
+
+	cases := make([]reflect.SelectCase, len(handles))
+	for i := range handles {
+		cases[i] = reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(handles[i].resultChan),
+		}
+	}
+	i, v, _ := reflect.Select(cases)
+	handles[i].state = v.Interface().(result[T])
+	handles[i].invoked = true
+	return i, handles[i].state.v, handles[i].state.e
+}
+
+// OrderedResults waits for all handles and returns them sorted in the order
+// their tasks were submitted to the pool, whatever order they come in.
+func OrderedResults[T any](handles []Handle[T]) []Handle[T] {
+	ordered := slices.Clone(handles)
+	slices.SortStableFunc(ordered, func(a, b Handle[T]) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+	for i := range ordered {
+		ordered[i].wait()
+	}
+	return ordered
+}
+
+// WaitAllTimeout waits at most d for all handles and returns the resolved
+// ones in their original order, together with whether that is all of them.
+// The tasks are not cancelled: the pending handles can still be waited on.
+// Results are cached in handles like with Get.
+func WaitAllTimeout[T any](handles []Handle[T], d time.Duration) ([]Handle[T], bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	resolved := make([]Handle[T], 0, len(handles))
+	for i := range handles {
+		// After the deadline, only pick up results that are already there.
+		if ctx.Err() != nil {
+			handles[i].TryGet()
+		} else {
+			handles[i].GetWithContext(ctx)
+		}
+		if handles[i].invoked {
+			resolved = append(resolved, handles[i])
+		}
+	}
+	return resolved, len(resolved) == len(handles)
+}
+
+// Collect waits for all handles and returns their values in order. Task
+// errors are wrapped with the task index and joined with errors.Join;
+// values of failed tasks are left as returned by the task.
+func Collect[T any](handles []Handle[T]) ([]T, error) {
+	values := make([]T, len(handles))
+	var errs []error
+	for i := range handles {
+		v, err := handles[i].Get()
+		values[i] = v
+		if err != nil {
+			errs = append(errs, fmt.Errorf("task %d: %w", i, err))
+		}
+	}
+	return values, errors.Join(errs...)
+}
+
+// Then returns a handle resolving with fn applied to the value of h, without
+// blocking the caller. If h resolves with an error, fn is not called and the
+// error is passed through. The returned handle cannot be canceled.
+// h must not be waited on elsewhere.
+func Then[T, U any](h Handle[T], fn func(T) (U, error)) Handle[U] {
+	resultChan := make(chan result[U], 1)
+	go func() {
+		v, err := h.Get()
+		if err != nil {
+			resultChan <- result[U]{e: err}
+			return
+		}
+		resultChan <- call(func() (U, error) { return fn(v) })
+	}()
+	return Handle[U]{
+		seq:        h.seq,
+		resultChan: resultChan,
+	}
+}
+
+// Reduce waits for the handles in order and folds their results into an
+// accumulator starting from init. fn also receives failed results, so the
+// caller decides whether an error is skipped, counted or recorded.
+func Reduce[T, A any](handles []Handle[T], init A, fn func(A, T, error) A) A {
+	acc := init
+	for i := range handles {
+		v, err := handles[i].Get()
+		acc = fn(acc, v, err)
+	}
+	return acc
+}
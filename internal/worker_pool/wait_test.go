@@ -0,0 +1,184 @@
+package worker_pool
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestWaitAnyReturnsFirstResolved(t *testing.T) {
+	pool := NewWorkerPool[string](2)
+	slow, _ := pool.Submit(func() (string, error) {
+		time.Sleep(200 * time.Millisecond)
+		return "slow", nil
+	})
+	fast, _ := pool.Submit(func() (string, error) {
+		time.Sleep(time.Millisecond)
+		return "fast", nil
+	})
+	pool.Done()
+
+	handles := []Handle[string]{slow, fast}
+	i, v, err := WaitAny(handles)
+	if i != 1 || v != "fast" || err != nil {
+		t.Errorf("WaitAny = %d, %q, %v; want 1, fast, nil", i, v, err)
+	}
+	// The result is cached in the handle.
+	if v, _ := handles[1].Get(); v != "fast" {
+		t.Errorf("Get after WaitAny = %q", v)
+	}
+	if v, _ := handles[0].Get(); v != "slow" {
+		t.Errorf("slow handle = %q", v)
+	}
+}
+
+func TestWaitAnyWithoutHandles(t *testing.T) {
+	if _, _, err := WaitAny[int](nil); !errors.Is(err, ErrNoHandles) {
+		t.Errorf("err = %v, want ErrNoHandles", err)
+	}
+}
+
+func TestOrderedResultsRestoresSubmissionOrder(t *testing.T) {
+	const n = 30
+	pool := NewWorkerPoolWithCapacity[int](6, n)
+	handles := make([]Handle[int], 0, n)
+	for i := range n {
+		delay := time.Duration(rand.IntN(5)) * time.Millisecond
+		h, err := pool.Submit(func() (int, error) {
+			time.Sleep(delay)
+			return i, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		handles = append(handles, h)
+	}
+	pool.Done()
+
+	// Shuffle the handles as if they were gathered in completion order.
+	rand.Shuffle(len(handles), func(i, j int) { handles[i], handles[j] = handles[j], handles[i] })
+	for i, h := range OrderedResults(handles) {
+		if v, _ := h.Get(); v != i {
+			t.Fatalf("position %d holds the result of task %d", i, v)
+		}
+	}
+}
+
+func TestCollectJoinsErrors(t *testing.T) {
+	pool := NewWorkerPool[int](3)
+	defer pool.Done()
+	errA, errB := errors.New("a"), errors.New("b")
+	handles, err := pool.SubmitBatch([]func() (int, error){
+		func() (int, error) { return 1, nil },
+		func() (int, error) { return 0, errA },
+		func() (int, error) { return 3, nil },
+		func() (int, error) { return 0, errB },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := Collect(handles)
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("err = %v, want both task errors", err)
+	}
+	if values[0] != 1 || values[2] != 3 {
+		t.Errorf("values = %v", values)
+	}
+	if msg := err.Error(); msg != "task 1: a\ntask 3: b" {
+		t.Errorf("message = %q", msg)
+	}
+}
+
+func TestCollectWithoutErrors(t *testing.T) {
+	pool := NewWorkerPool[int](1)
+	defer pool.Done()
+	h, _ := pool.Submit(func() (int, error) { return 1, nil })
+	if _, err := Collect([]Handle[int]{h}); err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+}
+
+func TestThenTransformsResult(t *testing.T) {
+	pool := NewWorkerPool[int](1)
+	defer pool.Done()
+	double := func(v int) (int, error) { return v * 2, nil }
+
+	h, _ := pool.Submit(func() (int, error) { return 21, nil })
+	doubled := Then(h, double)
+	if v, err := doubled.Get(); v != 42 || err != nil {
+		t.Errorf("Then = %d, %v; want 42, nil", v, err)
+	}
+
+	errTask := errors.New("task")
+	called := false
+	failed, _ := pool.Submit(func() (int, error) { return 0, errTask })
+	chained := Then(failed, func(v int) (string, error) {
+		called = true
+		return "", nil
+	})
+	if _, err := chained.Get(); !errors.Is(err, errTask) {
+		t.Errorf("err = %v, want the task error", err)
+	}
+	if called {
+		t.Error("fn called after a failed task")
+	}
+}
+
+func TestReduceSumsWithFailure(t *testing.T) {
+	pool := NewWorkerPool[int](2)
+	defer pool.Done()
+	errTask := errors.New("task")
+	var handles []Handle[int]
+	for i := 1; i <= 5; i++ {
+		h, _ := pool.Submit(func() (int, error) {
+			if i == 3 {
+				return 0, errTask
+			}
+			return i, nil
+		})
+		handles = append(handles, h)
+	}
+	type acc struct{ sum, failed int }
+	got := Reduce(handles, acc{}, func(a acc, v int, err error) acc {
+		if err != nil {
+			a.failed++
+			return a
+		}
+		a.sum += v
+		return a
+	})
+	if got.sum != 12 || got.failed != 1 {
+		t.Errorf("Reduce = %+v, want sum 12 with 1 failure", got)
+	}
+}
+
+func TestWaitAllTimeoutPartial(t *testing.T) {
+	pool := NewWorkerPool[int](4)
+	defer pool.Done()
+	release := make(chan struct{})
+	var handles []Handle[int]
+	for i := range 4 {
+		h, _ := pool.Submit(func() (int, error) {
+			if i%2 == 1 {
+				<-release
+			}
+			return i, nil
+		})
+		handles = append(handles, h)
+	}
+	resolved, complete := WaitAllTimeout(handles, 50*time.Millisecond)
+	if complete {
+		t.Error("reported complete with slow tasks pending")
+	}
+	if len(resolved) != 2 || resolved[0].Value() != 0 || resolved[1].Value() != 2 {
+		t.Errorf("resolved %d handles", len(resolved))
+	}
+
+	// The slow tasks were not cancelled.
+	close(release)
+	resolved, complete = WaitAllTimeout(handles, time.Second)
+	if !complete || len(resolved) != 4 || resolved[3].Value() != 3 {
+		t.Errorf("second wait: %d handles, complete %t", len(resolved), complete)
+	}
+}
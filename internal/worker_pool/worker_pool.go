@@ -1,8 +1,14 @@
 package worker_pool
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"runtime"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Workers int
@@ -11,25 +17,164 @@ type Capacity int
 var defaultWorkers = Workers(runtime.NumCPU())
 var defaultCapacity = Capacity(32)
 
+// ErrPoolClosed is returned by Submit after Done has been called.
+var ErrPoolClosed = errors.New("worker pool: closed")
+
+var errQueueFull = errors.New("worker pool: queue is full")
+
+// ErrPoolBusy is returned by Reset while the pool has not finished its work.
+var ErrPoolBusy = errors.New("worker pool: still running")
+
+var ErrInvalidWorkers = errors.New("worker pool: at least one worker is required")
+
+// ErrTaskCanceled resolves a handle canceled before its task started.
+var ErrTaskCanceled = fmt.Errorf("worker pool: task canceled: %w", context.Canceled)
+
 type WorkerPool[T any] interface {
 	Submit(func() (T, error)) (Handle[T], error)
+	SubmitWithPriority(func() (T, error), int) (Handle[T], error)
+	SubmitBefore(func() (T, error), time.Time) (Handle[T], error)
+	TrySubmit(func() (T, error)) (Handle[T], bool)
+	SubmitWithTimeout(func(context.Context) (T, error), time.Duration) (Handle[T], error)
+	SubmitBatch([]func() (T, error)) ([]Handle[T], error)
+	SubmitGroup(key string, procs []func() (T, error)) ([]Handle[T], error)
+	SubmitAll(ctx context.Context, gen func() (func() (T, error), bool), collect func(T, error)) error
+	Stats() Stats
+	OnComplete(func(index int, value T, err error))
+	Stream() <-chan Handle[T]
+	Resize(Workers) error
+	SetIdleTimeout(time.Duration)
+	Shutdown(context.Context) error
+	Reset() error
+	Drain()
 	Done()
 }
 
+// Stats is a point-in-time view of the pool counters. Completed counts
+// tasks that returned a nil error, Failed the rest, including tasks
+// dropped because of cancellation. Tasks still queued are
+// Submitted - Completed - Failed - InFlight.
+type Stats struct {
+	Submitted uint64
+	Completed uint64
+	Failed    uint64
+	InFlight  uint64
+}
+
 type result[T any] struct {
 	e error
 	v T
 }
 
+// task is a unit of work queued to the pool together with the channel
+// its result has to be delivered to.
+type task[T any] struct {
+	seq        uint64
+	priority   int
+	proc       func(context.Context) (T, error)
+	resultChan chan<- result[T]
+	ctl        *control
+
+	// group is set instead of proc on the queue entry of a task group,
+	// see SubmitGroup.
+	group *group[T]
+}
+
+type taskState int
+
+const (
+	taskPending taskState = iota
+	taskRunning
+	taskDone
+)
+
+// control is shared by a task and all copies of its handle to coordinate
+// cancellation with the worker running it.
+type control struct {
+	mu     sync.Mutex
+	state  taskState
+	cancel context.CancelFunc
+}
+
+// start moves a pending task to running with a child context of parent.
+// It returns false if the task was canceled while queued.
+func (c *control) start(parent context.Context) (context.Context, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state != taskPending {
+		return nil, false
+	}
+	ctx, cancel := context.WithCancel(parent)
+	c.state = taskRunning
+	c.cancel = cancel
+	return ctx, true
+}
+
+func (c *control) finish() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.state = taskDone
+}
+
+// Handle is bound to exactly one submitted task: Get always returns
+// the result of that task, regardless of completion order.
+//
+// Every task has its own single-slot result buffer, so a worker hands the
+// result over without waiting for the handle to be read and slow consumers
+// never stall the pool. The memory held for results is therefore bounded by
+// one result per handle not yet read.
+//
+// The result channels are deliberately not recycled through a sync.Pool.
+// A Handle is a value and its copies (a range loop variable, an element
+// of a slice) share the channel, so a channel reused for another task after
+// one copy returned from Get would hand that task's result to a later Get
+// on a stale copy. The channel is a single small allocation, collected
+// together with the last copy of the handle.
 type Handle[T any] struct {
-	resultChan <-chan result[T]
+	seq        uint64
+	resultChan chan result[T]
+	ctl        *control
 	state      result[T]
 	invoked    bool
 }
 
+// Cancel aborts the task. A task still waiting in the queue is never run
+// and its handle resolves with ErrTaskCanceled right away. For a running
+// task the context passed to it is cancelled, which only has an effect on
+// context-aware tasks such as the ones given to SubmitWithTimeout.
+func (h *Handle[T]) Cancel() {
+	if h.ctl == nil {
+		return
+	}
+	h.ctl.mu.Lock()
+	defer h.ctl.mu.Unlock()
+	switch h.ctl.state {
+	case taskPending:
+		h.ctl.state = taskDone
+		h.resultChan <- result[T]{e: ErrTaskCanceled}
+	case taskRunning:
+		h.ctl.cancel()
+	}
+}
+
+// discard resolves t with ErrTaskCanceled if it has not started yet. The
+// worker that later picks it up only reports the cancellation.
+func (t task[T]) discard() {
+	t.ctl.mu.Lock()
+	defer t.ctl.mu.Unlock()
+	if t.ctl.state == taskPending {
+		t.ctl.state = taskDone
+		t.resultChan <- result[T]{e: ErrTaskCanceled}
+	}
+}
+
 func (h *Handle[T]) wait() {
 	if !h.invoked {
 		h.state = <-h.resultChan
+		h.invoked = true
 	}
 }
 
@@ -38,36 +183,543 @@ func (h *Handle[T]) Get() (T, error) {
 	return h.state.v, h.state.e
 }
 
+// Value waits for the task like Get and returns only its value. Value and
+// Err share the cached result, so calling both waits once.
+func (h *Handle[T]) Value() T {
+	h.wait()
+	return h.state.v
+}
+
+// Err waits for the task like Get and returns only its error.
+func (h *Handle[T]) Err() error {
+	h.wait()
+	return h.state.e
+}
+
+// GetWithContext waits for the result until ctx is done. Giving up on
+// waiting does not cancel the task: a later Get still returns its result.
+func (h *Handle[T]) GetWithContext(ctx context.Context) (T, error) {
+	if !h.invoked {
+		select {
+		case h.state = <-h.resultChan:
+			h.invoked = true
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+	return h.state.v, h.state.e
+}
+
+// GetOrDefault returns the task value if the task succeeds within d and
+// fallback otherwise, including when it fails. The task keeps running.
+func (h *Handle[T]) GetOrDefault(d time.Duration, fallback T) T {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	v, err := h.GetWithContext(ctx)
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// TryGet returns the task result without blocking. ok is false while the
+// task is still pending; once it is true the result is cached like in Get.
+func (h *Handle[T]) TryGet() (v T, err error, ok bool) {
+	if !h.invoked {
+		select {
+		case h.state = <-h.resultChan:
+			h.invoked = true
+		default:
+			return v, nil, false
+		}
+	}
+	return h.state.v, h.state.e, true
+}
+
 type workerPoolImpl[T any] struct {
-	wg         *sync.WaitGroup
-	workers    Workers
-	submitChan chan func() (T, error)
-	resultChan chan result[T]
+	ctx     context.Context
+	wg      *sync.WaitGroup
+	queue   taskQueue[T]
+	shrink  chan struct{}
+	stopped chan struct{}
+	seq     atomic.Uint64
+
+	// slots bounds the number of queued tasks by the pool capacity. ready
+	// holds one token per task in queue and is closed to stop the workers.
+	slots chan struct{}
+	ready chan struct{}
+
+	submitted atomic.Uint64
+	inFlight  atomic.Uint64
+	completed atomic.Uint64
+	failed    atomic.Uint64
+
+	notifier notifier[T]
+
+	// mu guards closing of ready: Submit holds it for reading while
+	// sending, Done and the cancellation watcher take it for writing.
+	mu     sync.RWMutex
+	closed bool
+
+	// groupsMu guards groups, the task groups queued or running by key.
+	groupsMu sync.Mutex
+	groups   map[string]*group[T]
+
+	// resizeMu serializes Resize calls changing workers, the target
+	// number of workers. live is the number of workers running, which is
+	// lower than workers while some of them have exited on idleness.
+	resizeMu    sync.Mutex
+	workers     Workers
+	live        atomic.Int64
+	idleTimeout atomic.Int64
+	// idled is set once a worker has exited on idleness, from then on
+	// queuing a task may have to spawn one.
+	idled atomic.Bool
 }
 
+// Submit queues proc for execution with priority 0, blocking while the
+// queue is full. Once the pool context is cancelled, Submit returns an
+// error wrapping the context error and the task is not queued. After Done
+// it returns ErrPoolClosed.
 func (w *workerPoolImpl[T]) Submit(proc func() (T, error)) (Handle[T], error) {
-	w.submitChan <- proc
-	return Handle[T]{
-		resultChan: w.resultChan,
+	return w.enqueue(ignoreContext(proc), 0, true)
+}
+
+// SubmitWithPriority is like Submit, but workers pick queued tasks with
+// a higher priority first. Tasks of equal priority run in submission order.
+func (w *workerPoolImpl[T]) SubmitWithPriority(proc func() (T, error), priority int) (Handle[T], error) {
+	return w.enqueue(ignoreContext(proc), priority, true)
+}
+
+// SubmitBefore submits proc unless deadline has already passed, in which
+// case proc is not run and the returned handle is resolved right away with
+// context.DeadlineExceeded. Such a task is still numbered, counted as
+// submitted and failed, and reported to OnComplete like any other.
+func (w *workerPoolImpl[T]) SubmitBefore(proc func() (T, error), deadline time.Time) (Handle[T], error) {
+	if time.Now().Before(deadline) {
+		return w.Submit(proc)
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if err := w.accepting(); err != nil {
+		return Handle[T]{}, err
+	}
+	t, h := w.newTask(nil, 0)
+	res := result[T]{e: context.DeadlineExceeded}
+	t.ctl.state = taskDone
+	t.resultChan <- res
+	w.failed.Add(1)
+	w.notifier.emit(int(t.seq-1), res)
+	return h, nil
+}
+
+// TrySubmit queues proc only if it can be done without blocking. ok is
+// false when the queue is full or the pool no longer accepts tasks.
+func (w *workerPoolImpl[T]) TrySubmit(proc func() (T, error)) (Handle[T], bool) {
+	h, err := w.enqueue(ignoreContext(proc), 0, false)
+	return h, err == nil
+}
+
+func ignoreContext[T any](proc func() (T, error)) func(context.Context) (T, error) {
+	return func(context.Context) (T, error) {
+		return proc()
+	}
+}
+
+func (w *workerPoolImpl[T]) enqueue(proc func(context.Context) (T, error), priority int, block bool) (Handle[T], error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if err := w.accepting(); err != nil {
+		return Handle[T]{}, err
+	}
+	t, h := w.newTask(proc, priority)
+	if err := w.acquireSlot(block); err != nil {
+		w.submitted.Add(^uint64(0))
+		return Handle[T]{}, err
+	}
+	w.push(t)
+	return h, nil
+}
+
+// accepting reports why the pool no longer takes tasks, nil if it does.
+// The caller must hold w.mu for reading.
+func (w *workerPoolImpl[T]) accepting() error {
+	if err := w.ctx.Err(); err != nil {
+		return fmt.Errorf("worker pool: submit: %w", err)
+	}
+	if w.closed {
+		return ErrPoolClosed
+	}
+	return nil
+}
+
+// newTask numbers proc as the next submitted task and returns it together
+// with its handle.
+func (w *workerPoolImpl[T]) newTask(proc func(context.Context) (T, error), priority int) (task[T], Handle[T]) {
+	seq := w.seq.Add(1)
+	w.submitted.Add(1)
+	resultChan := make(chan result[T], 1)
+	t := task[T]{
+		seq:        seq,
+		priority:   priority,
+		proc:       proc,
+		resultChan: resultChan,
+		ctl:        &control{},
+	}
+	return t, Handle[T]{
+		seq:        seq,
+		resultChan: resultChan,
+		ctl:        t.ctl,
 		state:      result[T]{},
 		invoked:    false,
-	}, nil
+	}
+}
+
+// acquireSlot takes a place in the queue, waiting for one if block is set.
+// The caller must hold w.mu for reading.
+func (w *workerPoolImpl[T]) acquireSlot(block bool) error {
+	select {
+	case w.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	if !block {
+		return errQueueFull
+	}
+	select {
+	case w.slots <- struct{}{}:
+		return nil
+	case <-w.ctx.Done():
+		return fmt.Errorf("worker pool: submit: %w", w.ctx.Err())
+	}
+}
+
+// push queues t into a slot taken with acquireSlot and wakes up a worker.
+func (w *workerPoolImpl[T]) push(t task[T]) {
+	w.queue.push(t)
+	// Never blocks: there are no more tokens in ready than taken slots.
+	w.ready <- struct{}{}
+	w.respawn()
+}
+
+// SubmitBatch submits procs in order and returns their handles in the same
+// order. If the pool stops accepting tasks midway, the handles submitted so
+// far are returned together with the Submit error.
+func (w *workerPoolImpl[T]) SubmitBatch(procs []func() (T, error)) ([]Handle[T], error) {
+	handles := make([]Handle[T], 0, len(procs))
+	for _, proc := range procs {
+		h, err := w.Submit(proc)
+		if err != nil {
+			return handles, err
+		}
+		handles = append(handles, h)
+	}
+	return handles, nil
+}
+
+// SubmitWithTimeout queues a context-aware task which is given at most
+// timeout to complete once a worker picks it up. On expiry the handle
+// resolves with context.DeadlineExceeded and the worker is released,
+// even if proc ignores its context and keeps running.
+func (w *workerPoolImpl[T]) SubmitWithTimeout(proc func(context.Context) (T, error), timeout time.Duration) (Handle[T], error) {
+	return w.enqueue(func(ctx context.Context) (T, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return runWithContext(ctx, proc)
+	}, 0, true)
+}
+
+func runWithContext[T any](ctx context.Context, proc func(context.Context) (T, error)) (T, error) {
+	done := make(chan result[T], 1)
+	go func() {
+		done <- call(func() (T, error) { return proc(ctx) })
+	}()
+	select {
+	case r := <-done:
+		return r.v, r.e
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// OnComplete registers fn to be called for every task as it finishes, in
+// completion order, with the task submission index (counted from zero, and
+// again from zero after Reset). Tasks canceled or dropped on context
+// cancellation are reported with their error. Callbacks run on a separate
+// goroutine, so a slow fn delays later callbacks but never the workers;
+// the completions waiting for it are queued without a bound.
+func (w *workerPoolImpl[T]) OnComplete(fn func(index int, value T, err error)) {
+	w.notifier.register(fn)
+}
+
+// Stream returns a channel yielding a resolved handle for every task
+// finishing after the call, in completion order. It is closed once the pool
+// is done and all its workers have exited. The caller must drain the channel
+// completely: until then the other OnComplete callbacks are held up.
+func (w *workerPoolImpl[T]) Stream() <-chan Handle[T] {
+	out := make(chan Handle[T])
+	// closed is only accessed on the notifier goroutine.
+	closed := false
+	w.notifier.register(func(index int, v T, err error) {
+		if closed {
+			return
+		}
+		out <- Handle[T]{
+			seq:     uint64(index) + 1,
+			state:   result[T]{e: err, v: v},
+			invoked: true,
+		}
+	})
+
+	w.mu.RLock()
+	stopped := w.stopped
+	w.mu.RUnlock()
+	go func() {
+		<-stopped
+		w.notifier.afterPending(func() {
+			closed = true
+			close(out)
+		})
+	}()
+	return out
+}
+
+func (w *workerPoolImpl[T]) Stats() Stats {
+	return Stats{
+		Submitted: w.submitted.Load(),
+		Completed: w.completed.Load(),
+		Failed:    w.failed.Load(),
+		InFlight:  w.inFlight.Load(),
+	}
+}
+
+// Resize changes the number of workers. Growing spawns new workers right
+// away; shrinking asks the excess workers to exit, each after finishing
+// its current task. It is safe to call concurrently with Submit.
+func (w *workerPoolImpl[T]) Resize(n Workers) error {
+	if n < 1 {
+		return ErrInvalidWorkers
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return ErrPoolClosed
+	}
+
+	w.resizeMu.Lock()
+	defer w.resizeMu.Unlock()
+	switch diff := n - w.workers; {
+	case diff > 0:
+		w.spawn(diff)
+	case diff < 0:
+		shrink, stopped := w.shrink, w.stopped
+		go func() {
+			for range -diff {
+				select {
+				case shrink <- struct{}{}:
+				case <-stopped:
+					return
+				}
+			}
+		}()
+	}
+	w.workers = n
+	return nil
+}
+
+func (w *workerPoolImpl[T]) spawn(n Workers) {
+	w.live.Add(int64(n))
+	w.wg.Add(int(n))
+	for range n {
+		go w.runWorker()
+	}
 }
 
 func (w *workerPoolImpl[T]) Done() {
-	close(w.submitChan)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.close()
+}
+
+// Shutdown stops accepting tasks and waits until the workers have run every
+// queued task. If ctx is done first, the workers keep draining in the
+// background and Shutdown returns the wrapped context error.
+func (w *workerPoolImpl[T]) Shutdown(ctx context.Context) error {
+	w.mu.Lock()
+	w.close()
+	stopped := w.stopped
+	w.mu.Unlock()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("worker pool: shutdown: %w", ctx.Err())
+	}
+}
+
+// Reset makes a pool that has been shut down accept tasks again, with the
+// same number of workers and queue capacity. It fails with ErrPoolBusy while
+// the pool is open or its workers still run tasks, and with the context error
+// if the pool context is done. Stats counters and submission indexes start
+// again from zero.
+func (w *workerPoolImpl[T]) Reset() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.ctx.Err(); err != nil {
+		return fmt.Errorf("worker pool: reset: %w", err)
+	}
+	if !w.closed {
+		return ErrPoolBusy
+	}
+	select {
+	case <-w.stopped:
+	default:
+		return ErrPoolBusy
+	}
+
+	w.resizeMu.Lock()
+	defer w.resizeMu.Unlock()
+	w.slots = make(chan struct{}, cap(w.slots))
+	w.ready = make(chan struct{}, cap(w.ready))
+	w.shrink = make(chan struct{})
+	w.stopped = make(chan struct{})
+	w.closed = false
+	w.seq.Store(0)
+	w.submitted.Store(0)
+	w.completed.Store(0)
+	w.failed.Store(0)
+	w.start()
+	return nil
+}
+
+// close stops the pool from accepting tasks. The caller must hold w.mu for
+// writing.
+//
+// This is the only place ready is closed. enqueue sends on ready while
+// holding w.mu for reading and checks closed first, so no send can race with
+// the close. Results never need closing: each task has its own buffered
+// channel that is written exactly once by the worker that resolves it.
+func (w *workerPoolImpl[T]) close() {
+	if !w.closed {
+		w.closed = true
+		close(w.ready)
+	}
+}
+
+// watchContext stops accepting tasks once the pool context is cancelled,
+// so the workers can resolve the queued ones and exit.
+func (w *workerPoolImpl[T]) watchContext(stopped <-chan struct{}) {
+	select {
+	case <-w.ctx.Done():
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.stopped == stopped {
+			w.close()
+		}
+	case <-stopped:
+	}
 }
 
 func (w *workerPoolImpl[T]) runWorker() error {
 	defer w.wg.Done()
-	for task := range w.submitChan {
-		v, err := task()
-		w.resultChan <- result[T]{
-			e: err,
-			v: v,
+	for {
+		t, ok := w.next()
+		if !ok {
+			return nil
 		}
+		if t.group != nil {
+			w.runGroup(t.group)
+			continue
+		}
+		w.run(t)
+	}
+}
+
+// next waits for the next task of the calling worker. It returns false when
+// the worker has to exit: the pool is done, Resize shrank it, or the worker
+// has been idle for longer than the idle timeout.
+func (w *workerPoolImpl[T]) next() (task[T], bool) {
+	var idle <-chan time.Time
+	if d := time.Duration(w.idleTimeout.Load()); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		idle = timer.C
+	}
+	for {
+		select {
+		case <-w.shrink:
+			if w.retire() {
+				return task[T]{}, false
+			}
+		case <-idle:
+			if w.retire() {
+				w.idled.Store(true)
+				return task[T]{}, false
+			}
+			// The last worker stays, without a timeout.
+			idle = nil
+		case _, ok := <-w.ready:
+			if !ok {
+				w.live.Add(-1)
+				return task[T]{}, false
+			}
+			t := w.queue.pop()
+			<-w.slots
+			return t, true
+		}
+	}
+}
+
+// run executes t on the calling worker, or resolves it without running it
+// if the pool context is done or the task was canceled.
+func (w *workerPoolImpl[T]) run(t task[T]) {
+	if err := w.ctx.Err(); err != nil {
+		t.ctl.mu.Lock()
+		if t.ctl.state == taskPending {
+			t.ctl.state = taskDone
+			t.resultChan <- result[T]{e: err}
+		} else {
+			err = ErrTaskCanceled
+		}
+		t.ctl.mu.Unlock()
+		w.failed.Add(1)
+		w.notifier.emit(int(t.seq-1), result[T]{e: err})
+		return
+	}
+	ctx, ok := t.ctl.start(w.ctx)
+	if !ok {
+		w.failed.Add(1)
+		w.notifier.emit(int(t.seq-1), result[T]{e: ErrTaskCanceled})
+		return
+	}
+	w.inFlight.Add(1)
+	res := call(func() (T, error) { return t.proc(ctx) })
+	t.ctl.finish()
+	w.inFlight.Add(^uint64(0))
+	if res.e != nil {
+		w.failed.Add(1)
+	} else {
+		w.completed.Add(1)
+	}
+	t.resultChan <- res
+	w.notifier.emit(int(t.seq-1), res)
+}
+
+// call runs proc, turning a panic into an error so that the worker
+// survives and the handle still gets resolved.
+func call[T any](proc func() (T, error)) (res result[T]) {
+	defer func() {
+		if r := recover(); r != nil {
+			res = result[T]{e: fmt.Errorf("task panicked: %v\n%s", r, debug.Stack())}
+		}
+	}()
+	v, err := proc()
+	return result[T]{
+		e: err,
+		v: v,
 	}
-	return nil
 }
 
 func NewWorkerPool[T any](workers Workers) WorkerPool[T] {
@@ -75,19 +727,39 @@ func NewWorkerPool[T any](workers Workers) WorkerPool[T] {
 }
 
 func NewWorkerPoolWithCapacity[T any](workers Workers, capacity Capacity) WorkerPool[T] {
+	return NewWorkerPoolWithContext[T](context.Background(), workers, capacity)
+}
+
+// NewWorkerPoolWithContext creates a pool bound to ctx. After ctx is cancelled
+// workers stop running new tasks: every queued but not yet started task
+// resolves its handle with ctx.Err(). Capacity is the number of tasks that
+// can be queued before Submit blocks, at least one.
+func NewWorkerPoolWithContext[T any](ctx context.Context, workers Workers, capacity Capacity) WorkerPool[T] {
+	capacity = max(capacity, 1)
 	pool := &workerPoolImpl[T]{
-		wg:         &sync.WaitGroup{},
-		workers:    workers,
-		submitChan: make(chan func() (T, error), capacity),
-		resultChan: make(chan result[T], capacity),
-	}
-	pool.wg.Add(int(workers))
-	for range workers {
-		go pool.runWorker()
+		ctx:     ctx,
+		wg:      &sync.WaitGroup{},
+		workers: workers,
+		slots:   make(chan struct{}, capacity),
+		ready:   make(chan struct{}, capacity),
+		shrink:  make(chan struct{}),
+		stopped: make(chan struct{}),
+		groups:  make(map[string]*group[T]),
 	}
+	pool.start()
+	return pool
+}
+
+// start spawns the workers together with the goroutines closing stopped
+// once they all exit and watching for context cancellation.
+func (w *workerPoolImpl[T]) start() {
+	w.spawn(w.workers)
+	stopped := w.stopped
+	// The stopped channel of this generation has a single owner: the
+	// goroutine below, which closes it after the last worker has exited.
 	go func() {
-		pool.wg.Wait()
-		close(pool.resultChan)
+		w.wg.Wait()
+		close(stopped)
 	}()
-	return pool
+	go w.watchContext(stopped)
 }